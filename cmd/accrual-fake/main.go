@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/accrualfake"
+	"github.com/aleksandrpnshkn/gophermart/internal/logs"
+	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
+	"go.uber.org/zap"
+)
+
+func main() {
+	rootCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	runAddress := "localhost:8083"
+	envRunAddress, ok := os.LookupEnv("RUN_ADDRESS")
+	if ok {
+		runAddress = envRunAddress
+	}
+	flag.StringVar(&runAddress, "a", runAddress, "net address host:port")
+
+	configFile := os.Getenv("ACCRUAL_FAKE_CONFIG")
+	flag.StringVar(&configFile, "c", configFile, "JSON file with order scenarios, every order is processed with 500 points by default")
+
+	logLevel := "info"
+	flag.StringVar(&logLevel, "l", logLevel, "log level")
+
+	flag.Parse()
+
+	logger, err := logs.NewLogger(logLevel)
+	if err != nil {
+		log.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	config := accrualfake.DefaultConfig()
+	if configFile != "" {
+		config, err = accrualfake.LoadConfig(configFile)
+		if err != nil {
+			logger.Fatal("failed to load scenarios", zap.Error(err))
+		}
+	}
+
+	server := http.Server{
+		Addr:    runAddress,
+		Handler: middlewares.NewLogMiddleware(logger)(accrualfake.New(config)),
+	}
+
+	go func() {
+		logger.Info("fake accrual listening...", zap.String("addr", runAddress))
+
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("failed to run server", zap.Error(err))
+		}
+	}()
+
+	<-rootCtx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = server.Shutdown(shutdownCtx)
+	if err != nil {
+		logger.Error("failed to shutdown server", zap.Error(err))
+	}
+}
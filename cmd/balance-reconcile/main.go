@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/config"
+	"github.com/aleksandrpnshkn/gophermart/internal/logs"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/balance"
+	"go.uber.org/zap"
+)
+
+func main() {
+	rootCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// флаг регистрируется до config.New, который вызывает flag.Parse
+	fix := flag.Bool("fix", false, "rebuild mismatched balances from balance_logs")
+
+	config, err := config.New()
+	if err != nil {
+		log.Fatalf("failed to load app config: %v", err)
+	}
+
+	logger, err := logs.NewLogger(config.LogLevel)
+	if err != nil {
+		log.Fatalf("failed to create app logger: %v", err)
+	}
+	defer logger.Sync()
+
+	balanceStorage, err := balance.NewSQLStorage(rootCtx, config.DatabaseURI)
+	if err != nil {
+		logger.Fatal("failed to create balance storage", zap.Error(err))
+	}
+	defer balanceStorage.Close()
+
+	mismatches, err := balanceStorage.GetMismatches(rootCtx)
+	if err != nil {
+		logger.Fatal("failed to reconcile balances", zap.Error(err))
+	}
+
+	for _, mismatch := range mismatches {
+		logger.Warn("balance mismatch",
+			zap.Int64("user_id", mismatch.UserID),
+			zap.String("stored_current", mismatch.Stored.Current.String()),
+			zap.String("stored_withdrawn", mismatch.Stored.Withdrawn.String()),
+			zap.String("ledger_current", mismatch.Ledger.Current.String()),
+			zap.String("ledger_withdrawn", mismatch.Ledger.Withdrawn.String()),
+		)
+
+		if !*fix {
+			continue
+		}
+
+		err = balanceStorage.Rebuild(rootCtx, mismatch.UserID)
+		if err != nil {
+			logger.Fatal("failed to rebuild balance",
+				zap.Int64("user_id", mismatch.UserID),
+				zap.Error(err),
+			)
+		}
+
+		logger.Info("balance rebuilt", zap.Int64("user_id", mismatch.UserID))
+	}
+
+	logger.Info("balances reconciled",
+		zap.Int("mismatches", len(mismatches)),
+		zap.Bool("fixed", *fix),
+	)
+
+	if len(mismatches) > 0 && !*fix {
+		logger.Sync()
+		os.Exit(1)
+	}
+}
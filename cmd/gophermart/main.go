@@ -16,7 +16,10 @@ func main() {
 	rootCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	config := config.New()
+	config, err := config.New()
+	if err != nil {
+		log.Fatalf("failed to load app config: %v", err)
+	}
 
 	logger, err := logs.NewLogger(config.LogLevel)
 	if err != nil {
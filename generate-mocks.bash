@@ -18,19 +18,36 @@ fi
 mockgen -destination=internal/mocks/mock_users_storage.go -package=mocks -mock_names Storage=MockUsersStorage ./internal/storage/users Storage
 mockgen -destination=internal/mocks/mock_orders_storage.go -package=mocks -mock_names Storage=MockOrdersStorage ./internal/storage/orders Storage
 mockgen -destination=internal/mocks/mock_balance_storage.go -package=mocks -mock_names Storage=MockBalanceStorage ./internal/storage/balance Storage
+mockgen -destination=internal/mocks/mock_jobs_storage.go -package=mocks -mock_names Storage=MockJobsStorage ./internal/storage/jobs Storage
+mockgen -destination=internal/mocks/mock_sessions_storage.go -package=mocks -mock_names Storage=MockSessionsStorage ./internal/storage/sessions Storage
+mockgen -destination=internal/mocks/mock_api_keys_storage.go -package=mocks -mock_names Storage=MockAPIKeysStorage ./internal/storage/apikeys Storage
+mockgen -destination=internal/mocks/mock_login_attempts_storage.go -package=mocks -mock_names Storage=MockLoginAttemptsStorage ./internal/storage/loginattempts Storage
 
 mockgen -destination=internal/mocks/mock_user_reciever.go -package=mocks ./internal/handlers UserReceiver
 mockgen -destination=internal/mocks/mock_user_registerer.go -package=mocks ./internal/handlers UserRegisterer
 mockgen -destination=internal/mocks/mock_user_loginer.go -package=mocks ./internal/handlers UserLoginer
+mockgen -destination=internal/mocks/mock_password_changer.go -package=mocks ./internal/handlers PasswordChanger
+mockgen -destination=internal/mocks/mock_login_throttler.go -package=mocks ./internal/handlers LoginThrottler
+mockgen -destination=internal/mocks/mock_token_refresher.go -package=mocks ./internal/handlers TokenRefresher
+mockgen -destination=internal/mocks/mock_logouter.go -package=mocks ./internal/handlers Logouter
 mockgen -destination=internal/mocks/mock_token_parser.go -package=mocks ./internal/middlewares TokenParser
+mockgen -destination=internal/mocks/mock_api_key_parser.go -package=mocks ./internal/middlewares APIKeyParser
+mockgen -destination=internal/mocks/mock_api_keys_manager.go -package=mocks ./internal/handlers APIKeysManager
 
 mockgen -destination=internal/mocks/mock_orders_service.go -package=mocks ./internal/handlers OrdersService
 
 mockgen -destination=internal/mocks/mock_accrualer.go -package=mocks ./internal/services Accrualer
 mockgen -destination=internal/mocks/mock_withdrawer.go -package=mocks ./internal/handlers Withdrawer
 mockgen -destination=internal/mocks/mock_balancer.go -package=mocks ./internal/handlers Balancer
+mockgen -destination=internal/mocks/mock_balance_history_getter.go -package=mocks ./internal/handlers BalanceHistoryGetter
+mockgen -destination=internal/mocks/mock_statement_getter.go -package=mocks ./internal/handlers StatementGetter
 
 mockgen -destination=internal/mocks/mock_order_job_processor.go -package=mocks ./internal/services OrderJobProcessor
 mockgen -destination=internal/mocks/mock_orders_queue.go -package=mocks ./internal/handlers OrdersQueue
+mockgen -destination=internal/mocks/mock_dead_letters.go -package=mocks ./internal/handlers DeadLetters
+mockgen -destination=internal/mocks/mock_users_admin.go -package=mocks ./internal/handlers UsersAdmin
+mockgen -destination=internal/mocks/mock_balance_adjuster.go -package=mocks ./internal/handlers BalanceAdjuster
+mockgen -destination=internal/mocks/mock_withdrawal_refunder.go -package=mocks ./internal/handlers WithdrawalRefunder
+mockgen -destination=internal/mocks/mock_accrual_status_applier.go -package=mocks ./internal/handlers AccrualStatusApplier
 
 echo "Finish"
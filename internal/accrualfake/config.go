@@ -0,0 +1,137 @@
+package accrualfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	StatusRegistered = "REGISTERED"
+	StatusInvalid    = "INVALID"
+	StatusProcessing = "PROCESSING"
+	StatusProcessed  = "PROCESSED"
+)
+
+// Step один ответ сценария. Заказ проходит шаги по порядку, последний шаг повторяется бесконечно.
+type Step struct {
+	// Code HTTP код ответа, по умолчанию 200
+	Code int `json:"code"`
+
+	Status  string          `json:"status"`
+	Accrual decimal.Decimal `json:"accrual"`
+
+	// RetryAfter значение заголовка Retry-After для 429
+	RetryAfter int `json:"retry_after"`
+
+	// Latency задержка перед ответом, добавляется к общей
+	Latency Duration `json:"latency"`
+
+	// Repeat сколько запросов подряд отвечать этим шагом, по умолчанию 1
+	Repeat int `json:"repeat"`
+}
+
+type Config struct {
+	// Latency задержка перед каждым ответом
+	Latency Duration `json:"latency"`
+
+	// Default сценарий для заказов, которых нет в Orders
+	Default []Step `json:"default"`
+
+	// Orders сценарии для конкретных номеров заказов
+	Orders map[string][]Step `json:"orders"`
+}
+
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw string
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	duration, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+	return nil
+}
+
+// DefaultConfig каждый заказ проходит все статусы и получает 500 баллов
+func DefaultConfig() Config {
+	return Config{
+		Default: []Step{
+			{Status: StatusRegistered},
+			{Status: StatusProcessing},
+			{Status: StatusProcessed, Accrual: decimal.NewFromInt(500)},
+		},
+	}
+}
+
+// LoadConfig читает сценарии из JSON файла
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	rawConfig, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+
+	err = json.Unmarshal(rawConfig, &config)
+	if err != nil {
+		return config, fmt.Errorf("%s: %w", path, err)
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return config, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+func (c Config) Validate() error {
+	err := validateSteps(c.Default)
+	if err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+
+	for orderNumber, steps := range c.Orders {
+		err := validateSteps(steps)
+		if err != nil {
+			return fmt.Errorf("order %s: %w", orderNumber, err)
+		}
+	}
+
+	return nil
+}
+
+func validateSteps(steps []Step) error {
+	for i, step := range steps {
+		if step.code() != http.StatusOK {
+			continue
+		}
+
+		switch step.Status {
+		case StatusRegistered, StatusInvalid, StatusProcessing, StatusProcessed:
+		default:
+			return fmt.Errorf("step %d: unknown status %q", i, step.Status)
+		}
+	}
+
+	return nil
+}
+
+func (s Step) code() int {
+	if s.Code == 0 {
+		return http.StatusOK
+	}
+	return s.Code
+}
@@ -0,0 +1,32 @@
+package accrualfake
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("example scenarios", func(t *testing.T) {
+		config, err := LoadConfig("../../cmd/accrual-fake/scenarios.example.json")
+		require.NoError(t, err)
+
+		assert.Equal(t, Duration(20*time.Millisecond), config.Latency)
+		assert.Len(t, config.Default, 3)
+		assert.Equal(t, "729.98", config.Orders["12345678903"][0].Accrual.String())
+		assert.Equal(t, 60, config.Orders["4561261212345467"][0].RetryAfter)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "scenarios.json")
+		err := os.WriteFile(path, []byte(`{"orders": {"123": [{"status": "DONE"}]}}`), 0o600)
+		require.NoError(t, err)
+
+		_, err = LoadConfig(path)
+		assert.ErrorContains(t, err, `order 123: step 0: unknown status "DONE"`)
+	})
+}
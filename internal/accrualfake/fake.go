@@ -0,0 +1,145 @@
+package accrualfake
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Fake заменяет систему расчёта баллов: отвечает на GET /api/orders/{number} по сценариям из Config
+type Fake struct {
+	mu sync.Mutex
+
+	config   Config
+	requests map[string]int
+	router   http.Handler
+}
+
+type orderResponse struct {
+	Order   string       `json:"order"`
+	Status  string       `json:"status"`
+	Accrual *json.Number `json:"accrual,omitempty"`
+}
+
+func (f *Fake) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	f.router.ServeHTTP(res, req)
+}
+
+// Requests сколько раз запрашивали заказ
+func (f *Fake) Requests(orderNumber string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests[orderNumber]
+}
+
+func (f *Fake) getOrder(res http.ResponseWriter, req *http.Request) {
+	orderNumber := chi.URLParam(req, "number")
+
+	step, ok := f.nextStep(orderNumber)
+
+	latency := f.config.Latency + step.Latency
+	if latency > 0 {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-time.After(time.Duration(latency)):
+		}
+	}
+
+	if !ok {
+		res.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	switch step.code() {
+	case http.StatusOK:
+		responseData := orderResponse{
+			Order:  orderNumber,
+			Status: step.Status,
+		}
+		if step.Status == StatusProcessed {
+			accrual := json.Number(step.Accrual.String())
+			responseData.Accrual = &accrual
+		}
+
+		rawResponseData, err := json.Marshal(responseData)
+		if err != nil {
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		res.Header().Set("Content-Type", "application/json")
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	case http.StatusTooManyRequests:
+		if step.RetryAfter > 0 {
+			res.Header().Set("Retry-After", strconv.Itoa(step.RetryAfter))
+		}
+		res.Header().Set("Content-Type", "text/plain")
+		res.WriteHeader(http.StatusTooManyRequests)
+		res.Write([]byte("No more than N requests per minute allowed"))
+	default:
+		res.WriteHeader(step.code())
+	}
+}
+
+// nextStep возвращает шаг сценария для очередного запроса заказа, false - заказ не зарегистрирован
+func (f *Fake) nextStep(orderNumber string) (Step, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	requestIndex := f.requests[orderNumber]
+	f.requests[orderNumber]++
+
+	steps, ok := f.config.Orders[orderNumber]
+	if !ok {
+		steps = f.config.Default
+	}
+	if len(steps) == 0 {
+		return Step{}, false
+	}
+
+	for _, step := range steps {
+		repeat := max(step.Repeat, 1)
+		if requestIndex < repeat {
+			return step, true
+		}
+		requestIndex -= repeat
+	}
+
+	return steps[len(steps)-1], true
+}
+
+func New(config Config) *Fake {
+	fake := &Fake{
+		config:   config,
+		requests: map[string]int{},
+	}
+
+	router := chi.NewRouter()
+	router.Get("/api/orders/{number}", fake.getOrder)
+	fake.router = router
+
+	return fake
+}
+
+// TestServer фейк, запущенный на httptest сервере, для тестов в других пакетах
+type TestServer struct {
+	*httptest.Server
+	*Fake
+}
+
+func NewTestServer(config Config) *TestServer {
+	fake := New(config)
+
+	return &TestServer{
+		Server: httptest.NewServer(fake),
+		Fake:   fake,
+	}
+}
@@ -3,15 +3,19 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/config"
 	"github.com/aleksandrpnshkn/gophermart/internal/handlers"
 	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
 	"github.com/aleksandrpnshkn/gophermart/internal/services"
 	"github.com/aleksandrpnshkn/gophermart/internal/storage"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
@@ -19,9 +23,14 @@ import (
 
 const (
 	orderQueueWorkersCount = 3
-	orderQueueJobsDelay    = 10 * time.Second
+	orderQueuePollInterval = 1 * time.Second
+
+	recoveryBatchSize = 500
 
 	shutdownTimeout = 20 * time.Second
+
+	loginLockoutBaseDelay = 1 * time.Second
+	loginFailuresWindow   = 24 * time.Hour
 )
 
 func Run(
@@ -42,44 +51,146 @@ func Run(
 
 	uni := services.NewAppUni()
 	responser := services.NewResponser(uni)
-	validate := services.NewValidate(uni)
-	auther := services.NewAuther(storages.Users, config.JwtSecretKey)
+	validate := services.NewValidate(uni, services.PasswordPolicy{
+		MinLength:      config.PasswordMinLength,
+		MinCharClasses: config.PasswordMinCharClasses,
+	})
+	jwtKeys, err := newJwtKeys(config, logger)
+	if err != nil {
+		return fmt.Errorf("failed to load jwt keys: %w", err)
+	}
+	auther := services.NewAuther(
+		storages.Users,
+		storages.Sessions,
+		jwtKeys,
+		config.AccessTokenTTL,
+		config.RefreshTokenTTL,
+		config.PasswordHashCost,
+		logger,
+	)
+	loginThrottler := services.NewLoginThrottler(
+		storages.LoginAttempts,
+		services.LoginThrottlePolicy{
+			FreeFailures: config.LoginMaxFailures,
+			BaseDelay:    loginLockoutBaseDelay,
+			MaxLockout:   config.LoginMaxLockout,
+		},
+		services.LoginThrottlePolicy{
+			FreeFailures: config.LoginIPMaxFailures,
+			BaseDelay:    loginLockoutBaseDelay,
+			MaxLockout:   config.LoginMaxLockout,
+		},
+		loginFailuresWindow,
+	)
+	authCookies := middlewares.NewAuthCookies(middlewares.CookieSettings{
+		Secure: config.AuthCookieSecure,
+		Domain: config.AuthCookieDomain,
+		MaxAge: config.AuthCookieMaxAge,
+	})
 
-	accrualClient := http.Client{}
-	accrualService := services.NewAccrualService(&accrualClient, logger, config.AccrualSystemAddress)
+	accrualClient := services.NewAccrualHTTPClient(services.AccrualClientSettings{
+		ConnectTimeout: config.AccrualConnectTimeout,
+		ReadTimeout:    config.AccrualReadTimeout,
+		MaxConns:       orderQueueWorkersCount,
+	})
+	accrualLimiter := services.NewAccrualLimiter(config.AccrualRateLimit)
+	accrualBreaker := services.NewCircuitBreaker("accrual", config.AccrualBreakerFailures, config.AccrualBreakerOpenTimeout, logger)
+	accrualService := services.NewAccrualService(accrualClient, accrualLimiter, accrualBreaker, logger, config.AccrualSystemAddress)
 	ordersService := services.NewOrdersService(storages.Orders, accrualService, logger)
 
 	ordersProceessor := services.NewOrdersProcessor(ordersService)
 	ordersQueue := services.NewOrdersQueue(
 		ordersProceessor,
+		storages.Jobs,
 		logger,
-		orderQueueJobsDelay,
+		services.RetryPolicy{
+			BaseDelay:   config.OrderJobRetryBaseDelay,
+			MaxDelay:    config.OrderJobRetryMaxDelay,
+			MaxAttempts: config.OrderJobMaxAttempts,
+		},
+		orderQueuePollInterval,
 	)
+
+	recovered, err := services.RecoverOrders(appCtx, ordersService, ordersQueue, logger, recoveryBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to recover unfinished orders: %w", err)
+	}
+	logger.Info("recovered unfinished orders",
+		zap.Int("count", recovered),
+	)
+
 	services.RunQueue(appCtx, ordersQueue, orderQueueWorkersCount)
 
 	balancer := services.NewBalancer(ordersService, storages.Balance, logger)
+	statementService := services.NewStatementService(storages.Balance, storages.Orders, logger)
+	deadLetters := services.NewDeadLetterService(storages.Jobs, logger)
+	usersService := services.NewUsersService(storages.Users, logger)
+	apiKeysService := services.NewAPIKeysService(storages.APIKeys, storages.Users, logger)
 
 	router.Use(middlewares.NewLogMiddleware(logger))
 	router.Use(middleware.SetHeader("Content-Type", "application/json"))
+	router.Use(middlewares.NewMoneyFormatMiddleware(responses.MoneyFormat(config.MoneyFormat)))
 
 	router.NotFound(handlers.NotFound(responser))
 
+	router.Get("/.well-known/jwks.json", handlers.GetJWKS(responser, jwtKeys, logger))
 	router.Get("/api/ping", handlers.Ping())
+	router.Get("/api/health", handlers.Health(responser, accrualBreaker, logger))
 
-	router.Post("/api/user/login", handlers.Login(responser, validate, auther, logger))
-	router.Post("/api/user/register", handlers.Register(responser, validate, auther, logger))
+	router.Post("/api/user/login", handlers.Login(responser, validate, authCookies, auther, loginThrottler, logger))
+	router.Post("/api/user/register", handlers.Register(responser, validate, authCookies, auther, logger))
+	router.Post("/api/user/refresh", handlers.RefreshTokens(responser, authCookies, auther, logger))
+	router.Post("/api/user/logout", handlers.Logout(responser, authCookies, auther, logger))
+
+	// без секрета вебхуки отключены, заказы обновляются только опросом accrual
+	if config.AccrualWebhookSecret != "" {
+		webhookVerifier := services.NewWebhookVerifier(config.AccrualWebhookSecret, config.AccrualWebhookTolerance)
+		router.Post("/api/accrual/webhook", handlers.AccrualWebhook(responser, validate, webhookVerifier, ordersService, logger))
+	}
+
+	// маршруты, доступные партнёрам по API ключу
+	router.With(middlewares.NewScopedAuthMiddleware(responser, logger, auther, apiKeysService, types.APIKeyScopeOrdersWrite)).
+		Post("/api/user/orders", handlers.AddOrder(responser, auther, logger, ordersService, ordersQueue))
+	router.With(middlewares.NewScopedAuthMiddleware(responser, logger, auther, apiKeysService, types.APIKeyScopeBalanceRead)).
+		Get("/api/user/balance", handlers.GetBalance(responser, auther, balancer, logger))
 
 	router.Group(func(router chi.Router) {
 		router.Use(middlewares.NewAuthMiddleware(responser, logger, auther))
 
-		router.Post("/api/user/orders", handlers.AddOrder(responser, auther, logger, ordersService, ordersQueue))
+		router.Post("/api/user/password", handlers.ChangePassword(responser, validate, authCookies, auther, auther, logger))
+
+		router.Get("/api/user/api-keys", handlers.GetAPIKeys(responser, auther, apiKeysService, logger))
+		router.Post("/api/user/api-keys", handlers.CreateAPIKey(responser, validate, auther, apiKeysService, logger))
+		router.Delete("/api/user/api-keys/{id}", handlers.RevokeAPIKey(responser, validate, auther, apiKeysService, logger))
+
 		router.Get("/api/user/orders", handlers.GetUserOrders(responser, auther, logger, ordersService))
+		router.Get("/api/user/orders/{number}", handlers.GetUserOrder(responser, auther, logger, ordersService))
 
-		router.Get("/api/user/balance", handlers.GetBalance(responser, auther, balancer, logger))
 		router.Post("/api/user/balance/withdraw", handlers.Withdraw(responser, validate, auther, balancer, logger))
+		router.Get("/api/user/balance/history", handlers.GetBalanceHistory(responser, auther, balancer, logger))
+		router.Get("/api/user/balance/statement", handlers.GetBalanceStatement(responser, auther, statementService, logger))
 		router.Get("/api/user/withdrawals", handlers.GetWithdrawals(responser, auther, balancer, logger))
 	})
 
+	router.Group(func(router chi.Router) {
+		router.Use(middlewares.NewAuthMiddleware(responser, logger, auther))
+		router.Use(middlewares.NewAdminMiddleware(responser, logger, auther))
+
+		router.Get("/api/admin/users", handlers.AdminSearchUsers(responser, usersService, logger))
+		router.Get("/api/admin/users/{id}/orders", handlers.AdminGetUserOrders(responser, usersService, ordersService, logger))
+		router.Get("/api/admin/users/{id}/balance", handlers.AdminGetUserBalance(responser, usersService, balancer, logger))
+		router.Get("/api/admin/users/{id}/balance/adjustments", handlers.AdminGetBalanceAdjustments(responser, usersService, balancer, logger))
+		router.Post("/api/admin/users/{id}/balance/adjustments", handlers.AdminAdjustBalance(responser, validate, auther, usersService, balancer, logger))
+		router.Post("/api/admin/users/{id}/withdrawals/{number}/refund", handlers.AdminRefundWithdrawal(responser, validate, auther, usersService, balancer, logger))
+		router.Post("/api/admin/users/{id}/lock", handlers.AdminSetUserLocked(responser, auther, usersService, logger, true))
+		router.Post("/api/admin/users/{id}/unlock", handlers.AdminSetUserLocked(responser, auther, usersService, logger, false))
+
+		router.Get("/api/admin/dead-letters", handlers.GetDeadLetters(responser, deadLetters, logger))
+		router.Get("/api/admin/dead-letters/{job}/{number}", handlers.GetDeadLetter(responser, deadLetters, logger))
+		router.Post("/api/admin/dead-letters/{job}/{number}/requeue", handlers.RequeueDeadLetter(responser, deadLetters, logger))
+		router.Delete("/api/admin/dead-letters/{job}/{number}", handlers.DiscardDeadLetter(responser, deadLetters, logger))
+	})
+
 	server := http.Server{
 		Addr:    config.RunAddress,
 		Handler: router,
@@ -130,3 +241,20 @@ func Run(
 
 	return nil
 }
+
+func newJwtKeys(config *config.Config, logger *zap.Logger) (*services.JwtKeys, error) {
+	if config.JwtSigningKeyFile == "" {
+		logger.Warn("jwt signing key file is not set, tokens are signed with shared secret")
+		return services.NewHMACJwtKeys(config.JwtSecretKey), nil
+	}
+
+	verifyKeyFiles := []string{}
+	for _, file := range strings.Split(config.JwtVerifyKeyFiles, ",") {
+		file = strings.TrimSpace(file)
+		if file != "" {
+			verifyKeyFiles = append(verifyKeyFiles, file)
+		}
+	}
+
+	return services.LoadJwtKeys(config.JwtSigningKeyFile, verifyKeyFiles)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/requests"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const (
+	accrualTimestampHeader = "X-Accrual-Timestamp"
+	accrualSignatureHeader = "X-Accrual-Signature"
+
+	accrualWebhookMaxBodySize = 64 << 10
+)
+
+type AccrualStatusApplier interface {
+	ApplyAccrualStatus(
+		ctx context.Context,
+		orderNumber string,
+		accrualStatus string,
+		accrual decimal.Decimal,
+	) (models.Order, error)
+}
+
+func AccrualWebhook(
+	responser *services.Responser,
+	validate *validator.Validate,
+	verifier *services.WebhookVerifier,
+	applier AccrualStatusApplier,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		rawRequestData, err := io.ReadAll(http.MaxBytesReader(res, req.Body, accrualWebhookMaxBodySize))
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+		defer req.Body.Close()
+
+		// подпись проверяется по сырому телу, до разбора JSON
+		err = verifier.Verify(req.Header.Get(accrualTimestampHeader), req.Header.Get(accrualSignatureHeader), rawRequestData)
+		if err != nil {
+			logger.Warn("rejected accrual webhook", zap.Error(err))
+			responser.WriteUnauthorizedError(ctx, res)
+			return
+		}
+
+		var requestData requests.AccrualWebhook
+		err = json.Unmarshal(rawRequestData, &requestData)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		err = validate.StructCtx(ctx, requestData)
+		if err != nil {
+			responser.WriteValidationError(ctx, res, err)
+			return
+		}
+
+		order, err := applier.ApplyAccrualStatus(ctx, requestData.OrderNumber, requestData.Status, requestData.Accrual)
+		if err != nil {
+			if errors.Is(err, services.ErrOrderNotFound) {
+				responser.WriteNotFoundError(ctx, res)
+				return
+			}
+			if errors.Is(err, services.ErrAccrualNegativeAmount) {
+				responser.WriteEmptyValidationError(ctx, res)
+				return
+			}
+
+			logger.Error("failed to apply accrual webhook",
+				zap.String("order_number", requestData.OrderNumber),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		logger.Debug("accrual webhook applied",
+			zap.String("order_number", order.OrderNumber),
+			zap.String("order_status", string(order.Status)),
+		)
+
+		responser.WriteSuccess(ctx, res)
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestAccrualWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	validate := services.NewValidate(uni, services.PasswordPolicy{MinLength: 8, MinCharClasses: 2})
+	logger := zap.NewExample()
+
+	verifier := services.NewWebhookVerifier("secret", 5*time.Minute)
+
+	body := `{"order": "12345678903", "status": "PROCESSED", "accrual": 729.98}`
+
+	t.Run("signed update", func(t *testing.T) {
+		applier := mocks.NewMockAccrualStatusApplier(ctrl)
+		applier.EXPECT().
+			ApplyAccrualStatus(gomock.Any(), "12345678903", "PROCESSED", decimal.RequireFromString("729.98")).
+			Return(models.Order{OrderNumber: "12345678903", Status: types.OrderStatusProcessed}, nil)
+
+		timestamp, signature := verifier.Sign(time.Now(), []byte(body))
+
+		apitest.New().
+			HandlerFunc(AccrualWebhook(responser, validate, verifier, applier, logger)).
+			Post("/api/accrual/webhook").
+			Header("X-Accrual-Timestamp", timestamp).
+			Header("X-Accrual-Signature", signature).
+			Body(body).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		timestamp, signature := services.NewWebhookVerifier("guess", 5*time.Minute).Sign(time.Now(), []byte(body))
+
+		apitest.New().
+			HandlerFunc(AccrualWebhook(responser, validate, verifier, mocks.NewMockAccrualStatusApplier(ctrl), logger)).
+			Post("/api/accrual/webhook").
+			Header("X-Accrual-Timestamp", timestamp).
+			Header("X-Accrual-Signature", signature).
+			Body(body).
+			Expect(t).
+			Status(http.StatusUnauthorized).
+			End()
+	})
+
+	t.Run("replayed after window", func(t *testing.T) {
+		timestamp, signature := verifier.Sign(time.Now().Add(-time.Hour), []byte(body))
+
+		apitest.New().
+			HandlerFunc(AccrualWebhook(responser, validate, verifier, mocks.NewMockAccrualStatusApplier(ctrl), logger)).
+			Post("/api/accrual/webhook").
+			Header("X-Accrual-Timestamp", timestamp).
+			Header("X-Accrual-Signature", signature).
+			Body(body).
+			Expect(t).
+			Status(http.StatusUnauthorized).
+			End()
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		unknownStatusBody := `{"order": "12345678903", "status": "DONE"}`
+		timestamp, signature := verifier.Sign(time.Now(), []byte(unknownStatusBody))
+
+		apitest.New().
+			HandlerFunc(AccrualWebhook(responser, validate, verifier, mocks.NewMockAccrualStatusApplier(ctrl), logger)).
+			Post("/api/accrual/webhook").
+			Header("X-Accrual-Timestamp", timestamp).
+			Header("X-Accrual-Signature", signature).
+			Body(unknownStatusBody).
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	})
+
+	t.Run("unknown order", func(t *testing.T) {
+		applier := mocks.NewMockAccrualStatusApplier(ctrl)
+		applier.EXPECT().
+			ApplyAccrualStatus(gomock.Any(), "12345678903", "PROCESSED", gomock.Any()).
+			Return(models.Order{}, services.ErrOrderNotFound)
+
+		timestamp, signature := verifier.Sign(time.Now(), []byte(body))
+
+		apitest.New().
+			HandlerFunc(AccrualWebhook(responser, validate, verifier, applier, logger)).
+			Post("/api/accrual/webhook").
+			Header("X-Accrual-Timestamp", timestamp).
+			Header("X-Accrual-Signature", signature).
+			Body(body).
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+	})
+}
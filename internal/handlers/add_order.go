@@ -27,7 +27,9 @@ type OrdersService interface {
 
 	UpdateAccrual(ctx context.Context, order models.Order) (models.Order, error)
 
-	GetUserOrders(ctx context.Context, user models.User) ([]models.Order, error)
+	GetUserOrders(ctx context.Context, user models.User, filter models.OrdersFilter) (models.OrdersPage, error)
+
+	GetUserOrder(ctx context.Context, user models.User, orderNumber string) (models.Order, []models.OrderStatusChange, error)
 
 	HasProcessedStatus(order models.Order) bool
 }
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/requests"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+type BalanceAdjuster interface {
+	Adjust(
+		ctx context.Context,
+		user models.User,
+		amount decimal.Decimal,
+		reason string,
+		reference string,
+		operator models.User,
+	) error
+
+	GetAdjustments(ctx context.Context, user models.User) ([]models.BalanceChange, error)
+}
+
+func AdminAdjustBalance(
+	responser *services.Responser,
+	validate *validator.Validate,
+	userReceiver UserReceiver,
+	usersAdmin UsersAdmin,
+	balanceAdjuster BalanceAdjuster,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		operator, err := userReceiver.FromContext(ctx)
+		if err != nil {
+			logger.Error("failed to get user", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		user, ok := userFromURL(res, req, responser, usersAdmin, logger)
+		if !ok {
+			return
+		}
+
+		rawRequestData, err := io.ReadAll(req.Body)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+		defer req.Body.Close()
+
+		var requestData requests.BalanceAdjustment
+		err = json.Unmarshal(rawRequestData, &requestData)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		err = validate.StructCtx(ctx, requestData)
+		if err != nil {
+			responser.WriteValidationError(ctx, res, err)
+			return
+		}
+
+		err = balanceAdjuster.Adjust(
+			ctx,
+			user,
+			requestData.Amount,
+			requestData.Reason,
+			requestData.Reference,
+			operator,
+		)
+		if err != nil {
+			if errors.Is(err, services.ErrBalanceNotEnoughFunds) {
+				res.WriteHeader(http.StatusPaymentRequired)
+				return
+			}
+			if errors.Is(err, services.ErrBalanceZeroAmount) ||
+				errors.Is(err, services.ErrBalanceBadPrecision) {
+				responser.WriteEmptyValidationError(ctx, res)
+				return
+			}
+
+			logger.Error("failed to adjust balance",
+				zap.Int64("user_id", user.ID),
+				zap.Int64("operator_id", operator.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		responser.WriteSuccess(ctx, res)
+	}
+}
+
+func AdminGetBalanceAdjustments(
+	responser *services.Responser,
+	usersAdmin UsersAdmin,
+	balanceAdjuster BalanceAdjuster,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		user, ok := userFromURL(res, req, responser, usersAdmin, logger)
+		if !ok {
+			return
+		}
+
+		adjustments, err := balanceAdjuster.GetAdjustments(ctx, user)
+		if err != nil {
+			logger.Error("failed to get balance adjustments",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		moneyFormat := responses.MoneyFormatFromContext(ctx)
+		responseData := []responses.BalanceAdjustment{}
+		for _, adjustment := range adjustments {
+			responseData = append(responseData, responses.BalanceAdjustment{
+				Amount:      responses.NewMoney(adjustment.Amount, moneyFormat),
+				Reason:      adjustment.Reason,
+				Reference:   adjustment.Reference,
+				OperatorID:  adjustment.OperatorID,
+				ProcessedAt: adjustment.ProcessedAt.Format(time.RFC3339),
+			})
+		}
+
+		rawResponseData, err := json.Marshal(responseData)
+		if err != nil {
+			logger.Error("failed to marshal balance adjustments",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestAdminBalanceAdjustments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+	validate := services.NewValidate(uni, services.PasswordPolicy{MinLength: 8, MinCharClasses: 2})
+
+	user := models.User{
+		ID:    7,
+		Login: "customer",
+		Role:  types.UserRoleCustomer,
+	}
+	operator := models.User{
+		ID:    1,
+		Login: "admin",
+		Role:  types.UserRoleAdmin,
+	}
+
+	newRouter := func(usersAdmin UsersAdmin, adjuster BalanceAdjuster) *chi.Mux {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().FromContext(gomock.Any()).AnyTimes().Return(operator, nil)
+
+		router := chi.NewRouter()
+		router.Post("/api/admin/users/{id}/balance/adjustments",
+			AdminAdjustBalance(responser, validate, userReceiver, usersAdmin, adjuster, logger))
+		router.Get("/api/admin/users/{id}/balance/adjustments",
+			AdminGetBalanceAdjustments(responser, usersAdmin, adjuster, logger))
+		return router
+	}
+
+	t.Run("adjust balance", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		adjuster := mocks.NewMockBalanceAdjuster(ctrl)
+		adjuster.EXPECT().
+			Adjust(gomock.Any(), user, decimal.RequireFromString("-10.5"), "duplicate accrual", "TICKET-1", operator).
+			Return(nil)
+
+		apitest.New().
+			Handler(newRouter(usersAdmin, adjuster)).
+			Post("/api/admin/users/7/balance/adjustments").
+			JSON(`{"amount": -10.5, "reason": "duplicate accrual", "reference": "TICKET-1"}`).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("reason required", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		apitest.New().
+			Handler(newRouter(usersAdmin, mocks.NewMockBalanceAdjuster(ctrl))).
+			Post("/api/admin/users/7/balance/adjustments").
+			JSON(`{"amount": 10}`).
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	})
+
+	t.Run("not enough funds", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		adjuster := mocks.NewMockBalanceAdjuster(ctrl)
+		adjuster.EXPECT().
+			Adjust(gomock.Any(), user, gomock.Any(), gomock.Any(), gomock.Any(), operator).
+			Return(services.ErrBalanceNotEnoughFunds)
+
+		apitest.New().
+			Handler(newRouter(usersAdmin, adjuster)).
+			Post("/api/admin/users/7/balance/adjustments").
+			JSON(`{"amount": -1000, "reason": "chargeback"}`).
+			Expect(t).
+			Status(http.StatusPaymentRequired).
+			End()
+	})
+
+	t.Run("get adjustments", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		loc, _ := time.LoadLocation("Europe/Moscow")
+		processedAt := time.Date(2020, 12, 10, 15, 15, 45, 0, loc)
+
+		adjuster := mocks.NewMockBalanceAdjuster(ctrl)
+		adjuster.EXPECT().
+			GetAdjustments(gomock.Any(), user).
+			Return([]models.BalanceChange{
+				{
+					UserID:      user.ID,
+					Amount:      decimal.NewFromInt(25),
+					ProcessedAt: processedAt,
+					Type:        types.BalanceChangeTypeAdjustment,
+					Reason:      "goodwill",
+					Reference:   "TICKET-2",
+					OperatorID:  operator.ID,
+				},
+			}, nil)
+
+		apitest.New().
+			Handler(newRouter(usersAdmin, adjuster)).
+			Get("/api/admin/users/7/balance/adjustments").
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`[
+                {
+                    "amount": 25,
+                    "reason": "goodwill",
+                    "reference": "TICKET-2",
+                    "operator_id": 1,
+                    "processed_at": "2020-12-10T15:15:45+03:00"
+                }
+            ]`).
+			End()
+	})
+}
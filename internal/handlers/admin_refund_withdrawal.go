@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/requests"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+type WithdrawalRefunder interface {
+	Refund(
+		ctx context.Context,
+		user models.User,
+		orderNumber string,
+		reason string,
+		operator models.User,
+	) error
+}
+
+func AdminRefundWithdrawal(
+	responser *services.Responser,
+	validate *validator.Validate,
+	userReceiver UserReceiver,
+	usersAdmin UsersAdmin,
+	refunder WithdrawalRefunder,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		operator, err := userReceiver.FromContext(ctx)
+		if err != nil {
+			logger.Error("failed to get user", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		user, ok := userFromURL(res, req, responser, usersAdmin, logger)
+		if !ok {
+			return
+		}
+
+		orderNumber := chi.URLParam(req, "number")
+
+		rawRequestData, err := io.ReadAll(req.Body)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+		defer req.Body.Close()
+
+		var requestData requests.WithdrawalRefund
+		err = json.Unmarshal(rawRequestData, &requestData)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		err = validate.StructCtx(ctx, requestData)
+		if err != nil {
+			responser.WriteValidationError(ctx, res, err)
+			return
+		}
+
+		err = refunder.Refund(ctx, user, orderNumber, requestData.Reason, operator)
+		if err != nil {
+			if errors.Is(err, services.ErrWithdrawalNotFound) {
+				responser.WriteNotFoundError(ctx, res)
+				return
+			}
+			if errors.Is(err, services.ErrWithdrawalAlreadyRefunded) {
+				responser.WriteConflict(ctx, res)
+				return
+			}
+
+			logger.Error("failed to refund withdrawal",
+				zap.Int64("user_id", user.ID),
+				zap.String("order_number", orderNumber),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		responser.WriteSuccess(ctx, res)
+	}
+}
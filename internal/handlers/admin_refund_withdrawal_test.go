@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/go-chi/chi/v5"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestAdminRefundWithdrawal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+	validate := services.NewValidate(uni, services.PasswordPolicy{MinLength: 8, MinCharClasses: 2})
+
+	user := models.User{
+		ID:    7,
+		Login: "customer",
+		Role:  types.UserRoleCustomer,
+	}
+	operator := models.User{
+		ID:    1,
+		Login: "admin",
+		Role:  types.UserRoleAdmin,
+	}
+
+	newRouter := func(usersAdmin UsersAdmin, refunder WithdrawalRefunder) *chi.Mux {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().FromContext(gomock.Any()).AnyTimes().Return(operator, nil)
+
+		router := chi.NewRouter()
+		router.Post("/api/admin/users/{id}/withdrawals/{number}/refund",
+			AdminRefundWithdrawal(responser, validate, userReceiver, usersAdmin, refunder, logger))
+		return router
+	}
+
+	t.Run("refund withdrawal", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		refunder := mocks.NewMockWithdrawalRefunder(ctrl)
+		refunder.EXPECT().
+			Refund(gomock.Any(), user, "2377225624", "order cancelled", operator).
+			Return(nil)
+
+		apitest.New().
+			Handler(newRouter(usersAdmin, refunder)).
+			Post("/api/admin/users/7/withdrawals/2377225624/refund").
+			JSON(`{"reason": "order cancelled"}`).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("withdrawal not found", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		refunder := mocks.NewMockWithdrawalRefunder(ctrl)
+		refunder.EXPECT().
+			Refund(gomock.Any(), user, "2377225624", gomock.Any(), operator).
+			Return(services.ErrWithdrawalNotFound)
+
+		apitest.New().
+			Handler(newRouter(usersAdmin, refunder)).
+			Post("/api/admin/users/7/withdrawals/2377225624/refund").
+			JSON(`{"reason": "order cancelled"}`).
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+	})
+
+	t.Run("already refunded", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		refunder := mocks.NewMockWithdrawalRefunder(ctrl)
+		refunder.EXPECT().
+			Refund(gomock.Any(), user, "2377225624", gomock.Any(), operator).
+			Return(services.ErrWithdrawalAlreadyRefunded)
+
+		apitest.New().
+			Handler(newRouter(usersAdmin, refunder)).
+			Post("/api/admin/users/7/withdrawals/2377225624/refund").
+			JSON(`{"reason": "order cancelled"}`).
+			Expect(t).
+			Status(http.StatusConflict).
+			End()
+	})
+
+	t.Run("reason required", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		apitest.New().
+			Handler(newRouter(usersAdmin, mocks.NewMockWithdrawalRefunder(ctrl))).
+			Post("/api/admin/users/7/withdrawals/2377225624/refund").
+			JSON(`{}`).
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	})
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	usersSearchDefaultLimit = 50
+	usersSearchMaxLimit     = 500
+)
+
+var (
+	errInvalidUserID = errors.New("invalid user id")
+)
+
+type UsersAdmin interface {
+	SearchUsers(ctx context.Context, login string, limit int) ([]models.User, error)
+
+	GetUser(ctx context.Context, id int64) (models.User, error)
+
+	SetLocked(ctx context.Context, id int64, locked bool, operator models.User) error
+}
+
+func AdminSearchUsers(
+	responser *services.Responser,
+	usersAdmin UsersAdmin,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		limit, err := parseLimit(req, usersSearchDefaultLimit, usersSearchMaxLimit)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		users, err := usersAdmin.SearchUsers(ctx, req.URL.Query().Get("login"), limit)
+		if err != nil {
+			logger.Error("failed to search users", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		responseData := []responses.User{}
+		for _, user := range users {
+			responseData = append(responseData, newUserResponse(user))
+		}
+
+		rawResponseData, err := json.Marshal(responseData)
+		if err != nil {
+			logger.Error("failed to marshal users", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
+
+func AdminGetUserOrders(
+	responser *services.Responser,
+	usersAdmin UsersAdmin,
+	ordersService OrdersService,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		user, ok := userFromURL(res, req, responser, usersAdmin, logger)
+		if !ok {
+			return
+		}
+
+		filter, err := parseOrdersFilter(req)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		page, err := ordersService.GetUserOrders(ctx, user, filter)
+		if err != nil {
+			logger.Error("failed to get user orders",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		writePageHeaders(res, page.Total, page.Next)
+
+		rawResponseData, err := json.Marshal(newOrdersResponse(page.Orders, ordersService, responses.MoneyFormatFromContext(ctx)))
+		if err != nil {
+			logger.Error("failed to marshal user orders",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
+
+func AdminGetUserBalance(
+	responser *services.Responser,
+	usersAdmin UsersAdmin,
+	balancer Balancer,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		user, ok := userFromURL(res, req, responser, usersAdmin, logger)
+		if !ok {
+			return
+		}
+
+		balance, err := balancer.GetBalance(ctx, user)
+		if err != nil {
+			logger.Error("failed to get balance",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		rawResponseData, err := json.Marshal(newBalanceResponse(balance, responses.MoneyFormatFromContext(ctx)))
+		if err != nil {
+			logger.Error("failed to marshal user balance",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
+
+func AdminSetUserLocked(
+	responser *services.Responser,
+	userReceiver UserReceiver,
+	usersAdmin UsersAdmin,
+	logger *zap.Logger,
+	locked bool,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		operator, err := userReceiver.FromContext(ctx)
+		if err != nil {
+			logger.Error("failed to get user", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		userID, err := parseUserID(req)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		err = usersAdmin.SetLocked(ctx, userID, locked, operator)
+		if err != nil {
+			if errors.Is(err, services.ErrUserNotFound) {
+				responser.WriteNotFoundError(ctx, res)
+				return
+			}
+
+			logger.Error("failed to change user lock",
+				zap.Int64("user_id", userID),
+				zap.Bool("locked", locked),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		responser.WriteSuccess(ctx, res)
+	}
+}
+
+func parseUserID(req *http.Request) (int64, error) {
+	userID, err := strconv.ParseInt(chi.URLParam(req, "id"), 10, 64)
+	if err != nil || userID < 1 {
+		return 0, errInvalidUserID
+	}
+
+	return userID, nil
+}
+
+// userFromURL достаёт пользователя из пути запроса, при ошибке сам пишет ответ
+func userFromURL(
+	res http.ResponseWriter,
+	req *http.Request,
+	responser *services.Responser,
+	usersAdmin UsersAdmin,
+	logger *zap.Logger,
+) (models.User, bool) {
+	ctx := req.Context()
+
+	userID, err := parseUserID(req)
+	if err != nil {
+		responser.WriteBadRequestError(ctx, res)
+		return models.User{}, false
+	}
+
+	user, err := usersAdmin.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			responser.WriteNotFoundError(ctx, res)
+			return models.User{}, false
+		}
+
+		logger.Error("failed to get user",
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		responser.WriteInternalServerError(ctx, res)
+		return models.User{}, false
+	}
+
+	return user, true
+}
+
+func newUserResponse(user models.User) responses.User {
+	return responses.User{
+		ID:       user.ID,
+		Login:    user.Login,
+		Role:     string(user.Role),
+		IsLocked: user.IsLocked,
+	}
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestAdminUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+
+	user := models.User{
+		ID:    7,
+		Login: "customer",
+		Hash:  types.PasswordHash("hash"),
+		Role:  types.UserRoleCustomer,
+	}
+	operator := models.User{
+		ID:    1,
+		Login: "admin",
+		Role:  types.UserRoleAdmin,
+	}
+
+	userReceiver := mocks.NewMockUserReceiver(ctrl)
+	userReceiver.EXPECT().FromContext(gomock.Any()).AnyTimes().Return(operator, nil)
+
+	t.Run("search users", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().
+			SearchUsers(gomock.Any(), "cust", usersSearchDefaultLimit).
+			Return([]models.User{user}, nil)
+
+		router := chi.NewRouter()
+		router.Get("/api/admin/users", AdminSearchUsers(responser, usersAdmin, logger))
+
+		apitest.New().
+			Handler(router).
+			Get("/api/admin/users").
+			Query("login", "cust").
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`[
+                {
+                    "id": 7,
+                    "login": "customer",
+                    "role": "customer",
+                    "is_locked": false
+                }
+            ]`).
+			End()
+	})
+
+	t.Run("get user orders", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		loc, _ := time.LoadLocation("Europe/Moscow")
+		uploadedAt := time.Date(2020, 12, 10, 15, 15, 45, 0, loc)
+
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().
+			CountUserOrders(gomock.Any(), user, gomock.Any()).
+			Return(1, nil)
+		ordersStorage.EXPECT().
+			GetUserOrders(gomock.Any(), user, gomock.Any()).
+			Return([]models.Order{
+				{
+					OrderNumber: "1",
+					Status:      types.OrderStatusProcessed,
+					UploadedAt:  uploadedAt,
+					Accrual:     decimal.NewFromInt(3),
+				},
+			}, nil)
+		ordersService := services.NewOrdersService(ordersStorage, mocks.NewMockAccrualer(ctrl), logger)
+
+		router := chi.NewRouter()
+		router.Get("/api/admin/users/{id}/orders", AdminGetUserOrders(responser, usersAdmin, ordersService, logger))
+
+		apitest.New().
+			Handler(router).
+			Get("/api/admin/users/7/orders").
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`[
+                {
+                    "number": "1",
+                    "status": "PROCESSED",
+                    "accrual": 3,
+                    "uploaded_at": "2020-12-10T15:15:45+03:00"
+                }
+            ]`).
+			End()
+	})
+
+	t.Run("get user balance", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		balancer := mocks.NewMockBalancer(ctrl)
+		balancer.EXPECT().
+			GetBalance(gomock.Any(), user).
+			Return(models.Balance{
+				Current:   decimal.NewFromInt(500),
+				Withdrawn: decimal.NewFromInt(42),
+			}, nil)
+
+		router := chi.NewRouter()
+		router.Get("/api/admin/users/{id}/balance", AdminGetUserBalance(responser, usersAdmin, balancer, logger))
+
+		apitest.New().
+			Handler(router).
+			Get("/api/admin/users/7/balance").
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`{"current": 500, "withdrawn": 42}`).
+			End()
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().GetUser(gomock.Any(), int64(404)).Return(models.User{}, services.ErrUserNotFound)
+
+		router := chi.NewRouter()
+		router.Get("/api/admin/users/{id}/balance", AdminGetUserBalance(responser, usersAdmin, mocks.NewMockBalancer(ctrl), logger))
+
+		apitest.New().
+			Handler(router).
+			Get("/api/admin/users/404/balance").
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+
+		router := chi.NewRouter()
+		router.Post("/api/admin/users/{id}/lock", AdminSetUserLocked(responser, userReceiver, usersAdmin, logger, true))
+
+		apitest.New().
+			Handler(router).
+			Post("/api/admin/users/abc/lock").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("lock user", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().SetLocked(gomock.Any(), user.ID, true, operator).Return(nil)
+
+		router := chi.NewRouter()
+		router.Post("/api/admin/users/{id}/lock", AdminSetUserLocked(responser, userReceiver, usersAdmin, logger, true))
+
+		apitest.New().
+			Handler(router).
+			Post("/api/admin/users/7/lock").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("unlock user", func(t *testing.T) {
+		usersAdmin := mocks.NewMockUsersAdmin(ctrl)
+		usersAdmin.EXPECT().SetLocked(gomock.Any(), user.ID, false, operator).Return(nil)
+
+		router := chi.NewRouter()
+		router.Post("/api/admin/users/{id}/unlock", AdminSetUserLocked(responser, userReceiver, usersAdmin, logger, false))
+
+		apitest.New().
+			Handler(router).
+			Post("/api/admin/users/7/unlock").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+}
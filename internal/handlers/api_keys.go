@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/requests"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+type APIKeysManager interface {
+	Create(ctx context.Context, user models.User, name string, scopes []types.APIKeyScope) (models.APIKey, types.RawToken, error)
+
+	GetUserKeys(ctx context.Context, user models.User) ([]models.APIKey, error)
+
+	Revoke(ctx context.Context, user models.User, id string) error
+}
+
+func GetAPIKeys(
+	responser *services.Responser,
+	userReceiver UserReceiver,
+	apiKeysManager APIKeysManager,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		user, err := userReceiver.FromContext(ctx)
+		if err != nil {
+			logger.Error("failed to get user", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		keys, err := apiKeysManager.GetUserKeys(ctx, user)
+		if err != nil {
+			logger.Error("failed to get api keys",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		responseData := []responses.APIKey{}
+		for _, key := range keys {
+			responseData = append(responseData, newAPIKeyResponse(key))
+		}
+
+		rawResponseData, err := json.Marshal(responseData)
+		if err != nil {
+			logger.Error("failed to marshal api keys", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
+
+func CreateAPIKey(
+	responser *services.Responser,
+	validate *validator.Validate,
+	userReceiver UserReceiver,
+	apiKeysManager APIKeysManager,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		rawRequestData, err := io.ReadAll(req.Body)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+		defer req.Body.Close()
+
+		var requestData requests.CreateAPIKey
+		err = json.Unmarshal(rawRequestData, &requestData)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		err = validate.StructCtx(ctx, requestData)
+		if err != nil {
+			responser.WriteValidationError(ctx, res, err)
+			return
+		}
+
+		user, err := userReceiver.FromContext(ctx)
+		if err != nil {
+			logger.Error("failed to get user", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		scopes := []types.APIKeyScope{}
+		for _, scope := range requestData.Scopes {
+			scopes = append(scopes, types.APIKeyScope(scope))
+		}
+
+		key, rawKey, err := apiKeysManager.Create(ctx, user, requestData.Name, scopes)
+		if err != nil {
+			logger.Error("failed to create api key",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		rawResponseData, err := json.Marshal(responses.CreatedAPIKey{
+			APIKey: newAPIKeyResponse(key),
+			Key:    string(rawKey),
+		})
+		if err != nil {
+			logger.Error("failed to marshal api key", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		res.Header().Set("Cache-Control", "no-store")
+		res.WriteHeader(http.StatusCreated)
+		res.Write(rawResponseData)
+	}
+}
+
+func RevokeAPIKey(
+	responser *services.Responser,
+	validate *validator.Validate,
+	userReceiver UserReceiver,
+	apiKeysManager APIKeysManager,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		id := chi.URLParam(req, "id")
+		err := validate.VarCtx(ctx, id, "required,uuid")
+		if err != nil {
+			responser.WriteNotFoundError(ctx, res)
+			return
+		}
+
+		user, err := userReceiver.FromContext(ctx)
+		if err != nil {
+			logger.Error("failed to get user", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		err = apiKeysManager.Revoke(ctx, user, id)
+		if err != nil {
+			if errors.Is(err, services.ErrAPIKeyNotFound) {
+				responser.WriteNotFoundError(ctx, res)
+				return
+			}
+
+			logger.Error("failed to revoke api key",
+				zap.Int64("user_id", user.ID),
+				zap.String("api_key_id", id),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		responser.WriteNoContent(ctx, res)
+	}
+}
+
+func newAPIKeyResponse(key models.APIKey) responses.APIKey {
+	scopes := []string{}
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	responseData := responses.APIKey{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    scopes,
+		CreatedAt: key.CreatedAt.Format(time.RFC3339),
+	}
+	if !key.LastUsedAt.IsZero() {
+		responseData.LastUsedAt = key.LastUsedAt.Format(time.RFC3339)
+	}
+
+	return responseData
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/go-chi/chi/v5"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestAPIKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	validate := services.NewValidate(uni, services.PasswordPolicy{MinLength: 8, MinCharClasses: 2})
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+
+	user := models.User{
+		ID:    1,
+		Login: "admin",
+	}
+	key := models.APIKey{
+		ID:        "0b6a3a8e-3c8f-4c8f-9a5e-2f1f0d7c8b10",
+		UserID:    user.ID,
+		Name:      "shop",
+		Prefix:    "gm_AbCdEfGh",
+		Scopes:    []types.APIKeyScope{types.APIKeyScopeOrdersWrite},
+		CreatedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	withUser := func() *mocks.MockUserReceiver {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().FromContext(gomock.Any()).Return(user, nil)
+		return userReceiver
+	}
+
+	t.Run("list keys", func(t *testing.T) {
+		apiKeysManager := mocks.NewMockAPIKeysManager(ctrl)
+		apiKeysManager.EXPECT().GetUserKeys(gomock.Any(), user).Return([]models.APIKey{key}, nil)
+
+		handler := GetAPIKeys(responser, withUser(), apiKeysManager, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Get("/api/user/api-keys").
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`[{
+                "id": "0b6a3a8e-3c8f-4c8f-9a5e-2f1f0d7c8b10",
+                "name": "shop",
+                "prefix": "gm_AbCdEfGh",
+                "scopes": ["orders:write"],
+                "created_at": "2025-06-01T12:00:00Z"
+            }]`).
+			End()
+	})
+
+	t.Run("create key", func(t *testing.T) {
+		apiKeysManager := mocks.NewMockAPIKeysManager(ctrl)
+		apiKeysManager.EXPECT().
+			Create(gomock.Any(), user, "shop", []types.APIKeyScope{types.APIKeyScopeOrdersWrite}).
+			Return(key, types.RawToken("gm_AbCdEfGhsecret"), nil)
+
+		handler := CreateAPIKey(responser, validate, withUser(), apiKeysManager, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/api-keys").
+			Body(`{"name": "shop", "scopes": ["orders:write"]}`).
+			Expect(t).
+			Status(http.StatusCreated).
+			Header("Cache-Control", "no-store").
+			Body(`{
+                "id": "0b6a3a8e-3c8f-4c8f-9a5e-2f1f0d7c8b10",
+                "name": "shop",
+                "prefix": "gm_AbCdEfGh",
+                "scopes": ["orders:write"],
+                "created_at": "2025-06-01T12:00:00Z",
+                "key": "gm_AbCdEfGhsecret"
+            }`).
+			End()
+	})
+
+	t.Run("unknown scope", func(t *testing.T) {
+		handler := CreateAPIKey(responser, validate, mocks.NewMockUserReceiver(ctrl), mocks.NewMockAPIKeysManager(ctrl), logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/api-keys").
+			Body(`{"name": "shop", "scopes": ["admin"]}`).
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	})
+
+	t.Run("revoke key", func(t *testing.T) {
+		apiKeysManager := mocks.NewMockAPIKeysManager(ctrl)
+		apiKeysManager.EXPECT().Revoke(gomock.Any(), user, key.ID).Return(nil)
+
+		handler := RevokeAPIKey(responser, validate, withUser(), apiKeysManager, logger)
+
+		apitest.New().
+			Handler(withURLParams(handler, map[string]string{"id": key.ID})).
+			Delete("/api/user/api-keys/" + key.ID).
+			Expect(t).
+			Status(http.StatusNoContent).
+			End()
+	})
+
+	t.Run("revoke someone else's key", func(t *testing.T) {
+		apiKeysManager := mocks.NewMockAPIKeysManager(ctrl)
+		apiKeysManager.EXPECT().Revoke(gomock.Any(), user, key.ID).Return(services.ErrAPIKeyNotFound)
+
+		handler := RevokeAPIKey(responser, validate, withUser(), apiKeysManager, logger)
+
+		apitest.New().
+			Handler(withURLParams(handler, map[string]string{"id": key.ID})).
+			Delete("/api/user/api-keys/" + key.ID).
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+	})
+
+	t.Run("invalid key id", func(t *testing.T) {
+		handler := RevokeAPIKey(responser, validate, mocks.NewMockUserReceiver(ctrl), mocks.NewMockAPIKeysManager(ctrl), logger)
+
+		apitest.New().
+			Handler(withURLParams(handler, map[string]string{"id": "123"})).
+			Delete("/api/user/api-keys/123").
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+	})
+}
+
+func withURLParams(handler http.HandlerFunc, params map[string]string) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		routeCtx := chi.NewRouteContext()
+		for key, value := range params {
+			routeCtx.URLParams.Add(key, value)
+		}
+		handler(res, req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)))
+	})
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+)
+
+// writeAuthTokens отдаёт токены в теле для клиентов без кук: мобильного приложения и скриптов
+func writeAuthTokens(res http.ResponseWriter, tokens models.AuthTokens) error {
+	now := time.Now()
+
+	rawResponseData, err := json.Marshal(responses.AuthTokens{
+		AccessToken:           string(tokens.AccessToken),
+		TokenType:             "Bearer",
+		ExpiresIn:             int64(tokens.AccessTokenExpiresAt.Sub(now).Seconds()),
+		RefreshToken:          string(tokens.RefreshToken),
+		RefreshTokenExpiresIn: int64(tokens.RefreshTokenExpiresAt.Sub(now).Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	res.Header().Set("Cache-Control", "no-store")
+	res.WriteHeader(http.StatusOK)
+	res.Write(rawResponseData)
+
+	return nil
+}
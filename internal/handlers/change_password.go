@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/requests"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+type PasswordChanger interface {
+	ChangePassword(ctx context.Context, user models.User, currentPassword string, newPassword string) (models.AuthTokens, error)
+}
+
+// ChangePassword завершает все сессии пользователя, клиент получает токены новой сессии
+func ChangePassword(
+	responser *services.Responser,
+	validate *validator.Validate,
+	authCookies *middlewares.AuthCookies,
+	userReceiver UserReceiver,
+	passwordChanger PasswordChanger,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		rawRequestData, err := io.ReadAll(req.Body)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+		defer req.Body.Close()
+
+		var requestData requests.ChangePassword
+		err = json.Unmarshal(rawRequestData, &requestData)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		err = validate.StructCtx(ctx, requestData)
+		if err != nil {
+			responser.WriteValidationError(ctx, res, err)
+			return
+		}
+
+		user, err := userReceiver.FromContext(ctx)
+		if err != nil {
+			logger.Error("failed to get user", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		tokens, err := passwordChanger.ChangePassword(ctx, user, requestData.CurrentPassword, requestData.NewPassword)
+		if err != nil {
+			if errors.Is(err, services.ErrBadCredentials) {
+				responser.WriteForbiddenError(ctx, res)
+				return
+			}
+
+			logger.Error("failed to change password",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		// Клиент с Bearer токеном получает новые токены в теле, остальные - в куках
+		_, fromHeader := req.Header["Authorization"]
+		if fromHeader {
+			err = writeAuthTokens(res, tokens)
+			if err != nil {
+				logger.Error("failed to write auth tokens", zap.Error(err))
+				responser.WriteInternalServerError(ctx, res)
+			}
+			return
+		}
+
+		authCookies.Set(res, tokens)
+
+		rawResponseData, _ := responses.EncodeOkResponse()
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
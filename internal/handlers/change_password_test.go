@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	validate := services.NewValidate(uni, services.PasswordPolicy{MinLength: 8, MinCharClasses: 2})
+	responser := services.NewResponser(uni)
+	authCookies := middlewares.NewAuthCookies(middlewares.CookieSettings{})
+	logger := zap.NewExample()
+
+	user := models.User{
+		ID:    1,
+		Login: "admin",
+	}
+	tokens := models.AuthTokens{
+		AccessToken:           types.RawToken("new-access"),
+		AccessTokenExpiresAt:  time.Now().Add(time.Minute),
+		RefreshToken:          types.RawToken("new-refresh"),
+		RefreshTokenExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("same password", func(t *testing.T) {
+		handler := ChangePassword(responser, validate, authCookies, mocks.NewMockUserReceiver(ctrl), mocks.NewMockPasswordChanger(ctrl), logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/password").
+			Body(`{"current_password": "password1", "new_password": "password1"}`).
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	})
+
+	t.Run("password changed", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().FromContext(gomock.Any()).Return(user, nil)
+
+		passwordChanger := mocks.NewMockPasswordChanger(ctrl)
+		passwordChanger.EXPECT().
+			ChangePassword(gomock.Any(), user, "password1", "new-password!").
+			Return(tokens, nil)
+
+		handler := ChangePassword(responser, validate, authCookies, userReceiver, passwordChanger, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/password").
+			Body(`{"current_password": "password1", "new_password": "new-password!"}`).
+			Expect(t).
+			Status(http.StatusOK).
+			Cookie(middlewares.AuthCookieName, "new-access").
+			Cookie(middlewares.RefreshCookieName, "new-refresh").
+			End()
+	})
+
+	t.Run("bearer client gets tokens in body", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().FromContext(gomock.Any()).Return(user, nil)
+
+		passwordChanger := mocks.NewMockPasswordChanger(ctrl)
+		passwordChanger.EXPECT().
+			ChangePassword(gomock.Any(), user, "password1", "new-password!").
+			Return(tokens, nil)
+
+		handler := ChangePassword(responser, validate, authCookies, userReceiver, passwordChanger, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/password").
+			Header("Authorization", "Bearer old-access").
+			Body(`{"current_password": "password1", "new_password": "new-password!"}`).
+			Expect(t).
+			Status(http.StatusOK).
+			Assert(assertAuthTokens("new-access", "new-refresh")).
+			CookieNotPresent(middlewares.AuthCookieName).
+			End()
+	})
+
+	t.Run("wrong current password", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().FromContext(gomock.Any()).Return(user, nil)
+
+		passwordChanger := mocks.NewMockPasswordChanger(ctrl)
+		passwordChanger.EXPECT().
+			ChangePassword(gomock.Any(), user, "password2", "new-password!").
+			Return(models.AuthTokens{}, services.ErrBadCredentials)
+
+		handler := ChangePassword(responser, validate, authCookies, userReceiver, passwordChanger, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/password").
+			Body(`{"current_password": "password2", "new_password": "new-password!"}`).
+			Expect(t).
+			Status(http.StatusForbidden).
+			End()
+	})
+}
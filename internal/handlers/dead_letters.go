@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	deadLettersDefaultLimit = 100
+	deadLettersMaxLimit     = 1000
+)
+
+type DeadLetters interface {
+	GetDeadLetters(ctx context.Context, limit int) ([]models.OrderJob, error)
+
+	GetDeadLetter(ctx context.Context, orderNumber string, jobName string) (models.OrderJob, error)
+
+	Requeue(ctx context.Context, orderNumber string, jobName string) error
+
+	Discard(ctx context.Context, orderNumber string, jobName string) error
+}
+
+func GetDeadLetters(
+	responser *services.Responser,
+	deadLetters DeadLetters,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		limit, err := parseLimit(req, deadLettersDefaultLimit, deadLettersMaxLimit)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		failedJobs, err := deadLetters.GetDeadLetters(ctx, limit)
+		if err != nil {
+			logger.Error("failed to get dead letters", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		responseData := []responses.DeadLetter{}
+		for _, job := range failedJobs {
+			responseData = append(responseData, newDeadLetterResponse(job))
+		}
+
+		rawResponseData, err := json.Marshal(responseData)
+		if err != nil {
+			logger.Error("failed to marshal dead letters", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
+
+func GetDeadLetter(
+	responser *services.Responser,
+	deadLetters DeadLetters,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orderNumber := chi.URLParam(req, "number")
+		jobName := chi.URLParam(req, "job")
+
+		job, err := deadLetters.GetDeadLetter(ctx, orderNumber, jobName)
+		if err != nil {
+			if errors.Is(err, services.ErrDeadLetterNotFound) {
+				responser.WriteNotFoundError(ctx, res)
+				return
+			}
+
+			logger.Error("failed to get dead letter",
+				zap.String("order_number", orderNumber),
+				zap.String("job_name", jobName),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		rawResponseData, err := json.Marshal(newDeadLetterResponse(job))
+		if err != nil {
+			logger.Error("failed to marshal dead letter", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
+
+func RequeueDeadLetter(
+	responser *services.Responser,
+	deadLetters DeadLetters,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orderNumber := chi.URLParam(req, "number")
+		jobName := chi.URLParam(req, "job")
+
+		err := deadLetters.Requeue(ctx, orderNumber, jobName)
+		if err != nil {
+			if errors.Is(err, services.ErrDeadLetterNotFound) {
+				responser.WriteNotFoundError(ctx, res)
+				return
+			}
+
+			logger.Error("failed to requeue dead letter",
+				zap.String("order_number", orderNumber),
+				zap.String("job_name", jobName),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		responser.WriteSuccess(ctx, res)
+	}
+}
+
+func DiscardDeadLetter(
+	responser *services.Responser,
+	deadLetters DeadLetters,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		orderNumber := chi.URLParam(req, "number")
+		jobName := chi.URLParam(req, "job")
+
+		err := deadLetters.Discard(ctx, orderNumber, jobName)
+		if err != nil {
+			if errors.Is(err, services.ErrDeadLetterNotFound) {
+				responser.WriteNotFoundError(ctx, res)
+				return
+			}
+
+			logger.Error("failed to discard dead letter",
+				zap.String("order_number", orderNumber),
+				zap.String("job_name", jobName),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		responser.WriteSuccess(ctx, res)
+	}
+}
+
+func newDeadLetterResponse(job models.OrderJob) responses.DeadLetter {
+	return responses.DeadLetter{
+		OrderNumber: job.Order.OrderNumber,
+		OrderStatus: string(job.Order.Status),
+		UserID:      job.Order.UserID,
+		JobName:     job.JobName,
+		Attempts:    job.Attempts,
+		LastError:   job.LastError,
+		FailedAt:    job.FailedAt.Format(time.RFC3339),
+	}
+}
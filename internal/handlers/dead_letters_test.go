@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/jobs"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/go-chi/chi/v5"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestDeadLetters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+
+	loc, _ := time.LoadLocation("Europe/Moscow")
+	failedAt := time.Date(2020, 12, 10, 15, 15, 45, 0, loc)
+
+	failedJob := models.OrderJob{
+		Order: models.Order{
+			OrderNumber: "12345678903",
+			UserID:      1,
+			Status:      types.OrderStatusProcessing,
+		},
+		JobName:   "get_accrual",
+		Attempts:  50,
+		LastError: "order not found",
+		FailedAt:  failedAt,
+	}
+
+	newRouter := func(jobsStorage *mocks.MockJobsStorage) http.Handler {
+		deadLetters := services.NewDeadLetterService(jobsStorage, logger)
+
+		router := chi.NewRouter()
+		router.Get("/api/admin/dead-letters", GetDeadLetters(responser, deadLetters, logger))
+		router.Get("/api/admin/dead-letters/{job}/{number}", GetDeadLetter(responser, deadLetters, logger))
+		router.Post("/api/admin/dead-letters/{job}/{number}/requeue", RequeueDeadLetter(responser, deadLetters, logger))
+		router.Delete("/api/admin/dead-letters/{job}/{number}", DiscardDeadLetter(responser, deadLetters, logger))
+		return router
+	}
+
+	t.Run("list dead letters", func(t *testing.T) {
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		jobsStorage.EXPECT().
+			GetFailed(gomock.Any(), 10).
+			Return([]models.OrderJob{failedJob}, nil)
+
+		apitest.New().
+			Handler(newRouter(jobsStorage)).
+			Get("/api/admin/dead-letters").
+			Query("limit", "10").
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`[
+                {
+                    "order": "12345678903",
+                    "order_status": "PROCESSING",
+                    "user_id": 1,
+                    "job": "get_accrual",
+                    "attempts": 50,
+                    "last_error": "order not found",
+                    "failed_at": "2020-12-10T15:15:45+03:00"
+                }
+            ]`).
+			End()
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+
+		apitest.New().
+			Handler(newRouter(jobsStorage)).
+			Get("/api/admin/dead-letters").
+			Query("limit", "0").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+
+	t.Run("get dead letter", func(t *testing.T) {
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		jobsStorage.EXPECT().
+			GetFailedJob(gomock.Any(), "12345678903", "get_accrual").
+			Return(failedJob, nil)
+
+		apitest.New().
+			Handler(newRouter(jobsStorage)).
+			Get("/api/admin/dead-letters/get_accrual/12345678903").
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`{
+                "order": "12345678903",
+                "order_status": "PROCESSING",
+                "user_id": 1,
+                "job": "get_accrual",
+                "attempts": 50,
+                "last_error": "order not found",
+                "failed_at": "2020-12-10T15:15:45+03:00"
+            }`).
+			End()
+	})
+
+	t.Run("dead letter not found", func(t *testing.T) {
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		jobsStorage.EXPECT().
+			GetFailedJob(gomock.Any(), "1", "get_accrual").
+			Return(models.OrderJob{}, jobs.ErrJobNotFound)
+
+		apitest.New().
+			Handler(newRouter(jobsStorage)).
+			Get("/api/admin/dead-letters/get_accrual/1").
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+	})
+
+	t.Run("requeue dead letter", func(t *testing.T) {
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		jobsStorage.EXPECT().
+			Requeue(gomock.Any(), "12345678903", "get_accrual").
+			Return(nil)
+
+		apitest.New().
+			Handler(newRouter(jobsStorage)).
+			Post("/api/admin/dead-letters/get_accrual/12345678903/requeue").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("discard missing dead letter", func(t *testing.T) {
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		jobsStorage.EXPECT().
+			Discard(gomock.Any(), "12345678903", "get_accrual").
+			Return(jobs.ErrJobNotFound)
+
+		apitest.New().
+			Handler(newRouter(jobsStorage)).
+			Delete("/api/admin/dead-letters/get_accrual/12345678903").
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+	})
+}
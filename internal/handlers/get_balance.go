@@ -8,6 +8,7 @@ import (
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
 	"github.com/aleksandrpnshkn/gophermart/internal/responses"
 	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
@@ -15,11 +16,12 @@ type Withdrawer interface {
 	Withdraw(
 		ctx context.Context,
 		orderNumber string,
-		amountRaw float64,
+		amount decimal.Decimal,
 		user models.User,
+		idempotencyKey string,
 	) error
 
-	GetWithdrawals(ctx context.Context, user models.User) ([]models.BalanceChange, error)
+	GetWithdrawals(ctx context.Context, user models.User, filter models.WithdrawalsFilter) (models.WithdrawalsPage, error)
 }
 
 type Balancer interface {
@@ -49,12 +51,7 @@ func GetBalance(
 			return
 		}
 
-		responseData := responses.Balance{
-			Current:   balance.Current.InexactFloat64(),
-			Withdrawn: balance.Withdrawn.InexactFloat64(),
-		}
-
-		rawResponseData, err := json.Marshal(responseData)
+		rawResponseData, err := json.Marshal(newBalanceResponse(balance, responses.MoneyFormatFromContext(ctx)))
 		if err != nil {
 			logger.Error("failed to marshal user balance",
 				zap.Int64("user_id", user.ID),
@@ -68,3 +65,10 @@ func GetBalance(
 		res.Write(rawResponseData)
 	}
 }
+
+func newBalanceResponse(balance models.Balance, moneyFormat responses.MoneyFormat) responses.Balance {
+	return responses.Balance{
+		Current:   responses.NewMoney(balance.Current, moneyFormat),
+		Withdrawn: responses.NewMoney(balance.Withdrawn, moneyFormat),
+	}
+}
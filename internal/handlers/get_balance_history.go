@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+const (
+	balanceHistoryDefaultLimit = 100
+	balanceHistoryMaxLimit     = 1000
+)
+
+type BalanceHistoryGetter interface {
+	GetHistory(
+		ctx context.Context,
+		user models.User,
+		filter models.BalanceHistoryFilter,
+	) (models.BalanceHistoryPage, error)
+}
+
+func GetBalanceHistory(
+	responser *services.Responser,
+	userReceiver UserReceiver,
+	historyGetter BalanceHistoryGetter,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		user, err := userReceiver.FromContext(ctx)
+		if err != nil {
+			logger.Error("failed to get user", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		filter, err := parseBalanceHistoryFilter(req)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		page, err := historyGetter.GetHistory(ctx, user, filter)
+		if err != nil {
+			logger.Error("failed to get balance history",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		moneyFormat := responses.MoneyFormatFromContext(ctx)
+		responseData := []responses.BalanceHistoryEntry{}
+		for _, entry := range page.Entries {
+			responseData = append(responseData, responses.BalanceHistoryEntry{
+				Type:        string(entry.Type),
+				OrderNumber: entry.OrderNumber,
+				Amount:      responses.NewMoney(entry.Amount, moneyFormat),
+				Balance:     responses.NewMoney(entry.Balance, moneyFormat),
+				ProcessedAt: entry.ProcessedAt.Format(time.RFC3339),
+			})
+		}
+
+		rawResponseData, err := json.Marshal(responseData)
+		if err != nil {
+			logger.Error("failed to marshal balance history",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		writePageHeaders(res, page.Total, page.Next)
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
+
+// parseBalanceHistoryFilter читает параметры limit, cursor, from и to
+func parseBalanceHistoryFilter(req *http.Request) (models.BalanceHistoryFilter, error) {
+	var filter models.BalanceHistoryFilter
+	var err error
+
+	filter.Limit, err = parseLimit(req, balanceHistoryDefaultLimit, balanceHistoryMaxLimit)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.After, err = parseCursor(req)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.From, err = parseTime(req, "from")
+	if err != nil {
+		return filter, err
+	}
+
+	filter.To, err = parseTime(req, "to")
+	if err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestGetBalanceHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+
+	user := models.User{
+		ID:    1,
+		Login: "admin",
+		Hash:  types.PasswordHash("hash"),
+	}
+
+	t.Run("get balance history", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		entries := []models.BalanceHistoryEntry{
+			{
+				BalanceChange: models.BalanceChange{
+					ID:          "3c0f8f4e-8f0a-4a4b-9d0e-6b8f9c3a2d13",
+					UserID:      user.ID,
+					Amount:      decimal.NewFromInt(50),
+					ProcessedAt: time.Date(2020, 12, 11, 9, 0, 0, 0, time.UTC),
+					Type:        types.BalanceChangeTypeAdjustment,
+				},
+				Balance: decimal.NewFromInt(550),
+			},
+			{
+				BalanceChange: models.BalanceChange{
+					ID:          "2b9e7e3d-7e9f-4939-8c9d-5a7e8b292c12",
+					OrderNumber: "2377225624",
+					UserID:      user.ID,
+					Amount:      decimal.RequireFromString("-500.5"),
+					ProcessedAt: time.Date(2020, 12, 10, 9, 0, 0, 0, time.UTC),
+					Type:        types.BalanceChangeTypeWithdrawal,
+				},
+				Balance: decimal.RequireFromString("500"),
+			},
+			{
+				BalanceChange: models.BalanceChange{
+					ID:          "1a8d6d2c-6d8e-4828-7b8c-4a6d7a181b11",
+					OrderNumber: "12345678903",
+					UserID:      user.ID,
+					Amount:      decimal.RequireFromString("1000.5"),
+					ProcessedAt: time.Date(2020, 12, 9, 9, 0, 0, 0, time.UTC),
+					Type:        types.BalanceChangeTypeAccrual,
+				},
+				Balance: decimal.RequireFromString("1000.5"),
+			},
+		}
+
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		balanceStorage.EXPECT().
+			GetHistory(gomock.Any(), user, models.BalanceHistoryFilter{Limit: 3}).
+			Return(entries, nil)
+		balanceStorage.EXPECT().
+			CountHistory(gomock.Any(), user, gomock.Any()).
+			Return(3, nil)
+
+		balancer := services.NewBalancer(mocks.NewMockOrdersService(ctrl), balanceStorage, logger)
+
+		handler := GetBalanceHistory(responser, userReceiver, balancer, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Get("/api/user/balance/history").
+			Query("limit", "2").
+			Expect(t).
+			Status(http.StatusOK).
+			Header("X-Total-Count", "3").
+			Header("X-Next-Cursor", encodeCursor(models.PageCursor{
+				At:  time.Date(2020, 12, 10, 9, 0, 0, 0, time.UTC),
+				Key: "2b9e7e3d-7e9f-4939-8c9d-5a7e8b292c12",
+			})).
+			Body(`[
+                {
+                    "type": "adjustment",
+                    "amount": 50,
+                    "balance": 550,
+                    "processed_at": "2020-12-11T09:00:00Z"
+                },
+                {
+                    "type": "withdrawal",
+                    "order": "2377225624",
+                    "amount": -500.5,
+                    "balance": 500,
+                    "processed_at": "2020-12-10T09:00:00Z"
+                }
+            ]`).
+			End()
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		handler := GetBalanceHistory(responser, userReceiver, mocks.NewMockBalanceHistoryGetter(ctrl), logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Get("/api/user/balance/history").
+			Query("limit", "-1").
+			Expect(t).
+			Status(http.StatusBadRequest).
+			End()
+	})
+}
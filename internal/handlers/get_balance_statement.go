@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+const (
+	statementFormatCSV = "csv"
+	statementFormatPDF = "pdf"
+)
+
+var (
+	errStatementPeriodRequired = errors.New("statement period is required")
+)
+
+type StatementGetter interface {
+	GetStatement(
+		ctx context.Context,
+		user models.User,
+		from time.Time,
+		to time.Time,
+	) (models.Statement, error)
+}
+
+func GetBalanceStatement(
+	responser *services.Responser,
+	userReceiver UserReceiver,
+	statementGetter StatementGetter,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		user, err := userReceiver.FromContext(ctx)
+		if err != nil {
+			logger.Error("failed to get user", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		from, to, err := parseStatementPeriod(req)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		format := req.URL.Query().Get("format")
+		if format == "" {
+			format = statementFormatCSV
+		}
+
+		var contentType string
+		var writeStatement func(w io.Writer, statement models.Statement) error
+
+		switch format {
+		case statementFormatCSV:
+			contentType = "text/csv; charset=utf-8"
+			writeStatement = services.WriteStatementCSV
+		case statementFormatPDF:
+			contentType = "application/pdf"
+			writeStatement = services.WriteStatementPDF
+		default:
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		statement, err := statementGetter.GetStatement(ctx, user, from, to)
+		if err != nil {
+			if errors.Is(err, services.ErrStatementBadPeriod) {
+				responser.WriteBadRequestError(ctx, res)
+				return
+			}
+
+			logger.Error("failed to get balance statement",
+				zap.Int64("user_id", user.ID),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		// собираем файл целиком, чтобы при ошибке ещё можно было вернуть 500
+		var buf bytes.Buffer
+		err = writeStatement(&buf, statement)
+		if err != nil {
+			logger.Error("failed to render balance statement",
+				zap.Int64("user_id", user.ID),
+				zap.String("format", format),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		filename := fmt.Sprintf("statement-%s-%s.%s",
+			from.Format(time.DateOnly),
+			to.Format(time.DateOnly),
+			format,
+		)
+
+		res.Header().Set("Content-Type", contentType)
+		res.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		res.WriteHeader(http.StatusOK)
+		res.Write(buf.Bytes())
+	}
+}
+
+// parseStatementPeriod читает обязательные параметры from и to
+func parseStatementPeriod(req *http.Request) (time.Time, time.Time, error) {
+	from, err := parseTime(req, "from")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	to, err := parseTime(req, "to")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if from.IsZero() || to.IsZero() {
+		return time.Time{}, time.Time{}, errStatementPeriodRequired
+	}
+
+	return from, to, nil
+}
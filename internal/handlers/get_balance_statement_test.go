@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestGetBalanceStatement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+
+	user := models.User{
+		ID:    1,
+		Login: "customer",
+		Hash:  types.PasswordHash("hash"),
+	}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	statement := models.Statement{
+		Login:          user.Login,
+		From:           from,
+		To:             to,
+		OpeningBalance: decimal.Zero,
+		Credits:        decimal.NewFromInt(10),
+		Debits:         decimal.Zero,
+		ClosingBalance: decimal.NewFromInt(10),
+	}
+
+	t.Run("csv statement", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().FromContext(gomock.Any()).Return(user, nil)
+
+		statementGetter := mocks.NewMockStatementGetter(ctrl)
+		statementGetter.EXPECT().GetStatement(gomock.Any(), user, from, to).Return(statement, nil)
+
+		handler := GetBalanceStatement(responser, userReceiver, statementGetter, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Get("/api/user/balance/statement").
+			Query("from", "2025-01-01").
+			Query("to", "2025-02-01").
+			Expect(t).
+			Status(http.StatusOK).
+			Header("Content-Type", "text/csv; charset=utf-8").
+			Header("Content-Disposition", `attachment; filename="statement-2025-01-01-2025-02-01.csv"`).
+			Body(`login,from,to,opening_balance,credits,debits,closing_balance,orders
+customer,2025-01-01T00:00:00Z,2025-02-01T00:00:00Z,0.00,10.00,0.00,10.00,0
+
+processed_at,type,order,amount,balance
+`).
+			End()
+	})
+
+	t.Run("pdf statement", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().FromContext(gomock.Any()).Return(user, nil)
+
+		statementGetter := mocks.NewMockStatementGetter(ctrl)
+		statementGetter.EXPECT().GetStatement(gomock.Any(), user, from, to).Return(statement, nil)
+
+		handler := GetBalanceStatement(responser, userReceiver, statementGetter, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Get("/api/user/balance/statement").
+			Query("from", "2025-01-01T03:00:00+03:00").
+			Query("to", "2025-02-01").
+			Query("format", "pdf").
+			Expect(t).
+			Status(http.StatusOK).
+			Header("Content-Type", "application/pdf").
+			Header("Content-Disposition", `attachment; filename="statement-2025-01-01-2025-02-01.pdf"`).
+			End()
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().FromContext(gomock.Any()).AnyTimes().Return(user, nil)
+
+		handler := GetBalanceStatement(responser, userReceiver, mocks.NewMockStatementGetter(ctrl), logger)
+
+		invalidQueries := []map[string]string{
+			{"from": "2025-01-01"},
+			{"from": "2025-01-01", "to": "february"},
+			{"from": "2025-01-01", "to": "2025-02-01", "format": "xlsx"},
+		}
+		for _, query := range invalidQueries {
+			apitest.New().
+				HandlerFunc(handler).
+				Get("/api/user/balance/statement").
+				QueryParams(query).
+				Expect(t).
+				Status(http.StatusBadRequest).
+				End()
+		}
+	})
+}
@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
 	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
 	"github.com/aleksandrpnshkn/gophermart/internal/services"
 	"github.com/aleksandrpnshkn/gophermart/internal/types"
 	"github.com/shopspring/decimal"
@@ -57,4 +59,38 @@ func TestGetBalance(t *testing.T) {
             }`).
 			End()
 	})
+	t.Run("money as strings", func(t *testing.T) {
+		userReciever := mocks.NewMockUserReceiver(ctrl)
+		userReciever.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		// во float сумма 0.1 и 0.2 даёт 0.30000000000000004
+		balance := models.Balance{
+			Current:   decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2")),
+			Withdrawn: decimal.RequireFromString("1234567890123456.78"),
+		}
+
+		balancer := mocks.NewMockBalancer(ctrl)
+		balancer.EXPECT().
+			GetBalance(gomock.Any(), user).
+			Return(balance, nil)
+
+		handler := middlewares.NewMoneyFormatMiddleware(responses.MoneyFormatNumber)(
+			GetBalance(responser, userReciever, balancer, logger),
+		)
+
+		apitest.New().
+			Handler(handler).
+			Post("/api/user/balance").
+			Header("Accept", "application/json; money=string").
+			Expect(t).
+			Status(http.StatusOK).
+			Header("Vary", "Accept").
+			Body(`{
+                "current": "0.3",
+                "withdrawn": "1234567890123456.78"
+            }`).
+			End()
+	})
 }
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// GetUserOrder возвращает заказ пользователя вместе с историей его статусов
+func GetUserOrder(
+	responser *services.Responser,
+	userReceiver UserReceiver,
+	logger *zap.Logger,
+	ordersService OrdersService,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		user, err := userReceiver.FromContext(ctx)
+		if err != nil {
+			logger.Error("failed to get user", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		orderNumber := chi.URLParam(req, "number")
+
+		order, history, err := ordersService.GetUserOrder(ctx, user, orderNumber)
+		if err != nil {
+			if errors.Is(err, services.ErrOrderNotFound) {
+				responser.WriteNotFoundError(ctx, res)
+				return
+			}
+
+			logger.Error("failed to get user order",
+				zap.Int64("user_id", user.ID),
+				zap.String("order_number", orderNumber),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		responseData := responses.OrderDetails{
+			Order:   newOrderResponse(order, ordersService, responses.MoneyFormatFromContext(ctx)),
+			History: newOrderHistoryResponse(history),
+		}
+
+		rawResponseData, err := json.Marshal(responseData)
+		if err != nil {
+			logger.Error("failed to marshal user order",
+				zap.Int64("user_id", user.ID),
+				zap.String("order_number", orderNumber),
+				zap.Error(err),
+			)
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
+
+func newOrderHistoryResponse(history []models.OrderStatusChange) []responses.OrderStatusChange {
+	responseData := []responses.OrderStatusChange{}
+
+	for _, change := range history {
+		responseData = append(responseData, responses.OrderStatusChange{
+			Status:    string(change.Status),
+			Source:    string(change.Source),
+			ChangedAt: change.ChangedAt.Format(time.RFC3339),
+		})
+	}
+
+	return responseData
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/orders"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestGetUserOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+
+	user := models.User{
+		ID:    1,
+		Login: "admin",
+		Hash:  types.PasswordHash("hash"),
+	}
+
+	uploadedAt := time.Date(2020, 12, 10, 12, 15, 45, 0, time.UTC)
+
+	order := models.Order{
+		OrderNumber: "12345678903",
+		UserID:      user.ID,
+		Status:      types.OrderStatusProcessed,
+		Accrual:     decimal.RequireFromString("729.98"),
+		UploadedAt:  uploadedAt,
+	}
+
+	t.Run("order with history", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		history := []models.OrderStatusChange{
+			{
+				Status:    types.OrderStatusNew,
+				Source:    types.OrderStatusSourceUser,
+				ChangedAt: uploadedAt,
+			},
+			{
+				Status:    types.OrderStatusProcessing,
+				Source:    types.OrderStatusSourcePoller,
+				ChangedAt: uploadedAt.Add(time.Second),
+			},
+			{
+				Status:    types.OrderStatusProcessed,
+				Source:    types.OrderStatusSourceWebhook,
+				ChangedAt: uploadedAt.Add(time.Minute),
+			},
+		}
+
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().
+			GetByNumber(gomock.Any(), order.OrderNumber).
+			Return(order, nil)
+		ordersStorage.EXPECT().
+			GetStatusHistory(gomock.Any(), order.OrderNumber).
+			Return(history, nil)
+		ordersService := services.NewOrdersService(ordersStorage, mocks.NewMockAccrualer(ctrl), logger)
+
+		handler := GetUserOrder(responser, userReceiver, logger, ordersService)
+
+		apitest.New().
+			Handler(withURLParams(handler, map[string]string{"number": order.OrderNumber})).
+			Get("/api/user/orders/" + order.OrderNumber).
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`{
+                "number": "12345678903",
+                "status": "PROCESSED",
+                "accrual": 729.98,
+                "uploaded_at": "2020-12-10T12:15:45Z",
+                "history": [
+                    {
+                        "status": "NEW",
+                        "source": "user",
+                        "changed_at": "2020-12-10T12:15:45Z"
+                    },
+                    {
+                        "status": "PROCESSING",
+                        "source": "poller",
+                        "changed_at": "2020-12-10T12:15:46Z"
+                    },
+                    {
+                        "status": "PROCESSED",
+                        "source": "webhook",
+                        "changed_at": "2020-12-10T12:16:45Z"
+                    }
+                ]
+            }`).
+			End()
+	})
+
+	t.Run("order of another user", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		anotherUserOrder := order
+		anotherUserOrder.UserID = 2
+
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().
+			GetByNumber(gomock.Any(), order.OrderNumber).
+			Return(anotherUserOrder, nil)
+		ordersService := services.NewOrdersService(ordersStorage, mocks.NewMockAccrualer(ctrl), logger)
+
+		handler := GetUserOrder(responser, userReceiver, logger, ordersService)
+
+		apitest.New().
+			Handler(withURLParams(handler, map[string]string{"number": order.OrderNumber})).
+			Get("/api/user/orders/" + order.OrderNumber).
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+	})
+
+	t.Run("unknown order", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().
+			GetByNumber(gomock.Any(), "79927398713").
+			Return(models.Order{}, orders.ErrOrderNotFound)
+		ordersService := services.NewOrdersService(ordersStorage, mocks.NewMockAccrualer(ctrl), logger)
+
+		handler := GetUserOrder(responser, userReceiver, logger, ordersService)
+
+		apitest.New().
+			Handler(withURLParams(handler, map[string]string{"number": "79927398713"})).
+			Get("/api/user/orders/79927398713").
+			Expect(t).
+			Status(http.StatusNotFound).
+			End()
+	})
+}
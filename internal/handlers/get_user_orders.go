@@ -2,14 +2,27 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
 	"github.com/aleksandrpnshkn/gophermart/internal/responses"
 	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
 	"go.uber.org/zap"
 )
 
+const (
+	ordersDefaultLimit = 100
+	ordersMaxLimit     = 1000
+)
+
+var (
+	errInvalidOrderStatus = errors.New("invalid order status")
+)
+
 func GetUserOrders(
 	responser *services.Responser,
 	userReceiver UserReceiver,
@@ -25,7 +38,13 @@ func GetUserOrders(
 			return
 		}
 
-		orders, err := ordersService.GetUserOrders(ctx, user)
+		filter, err := parseOrdersFilter(req)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		page, err := ordersService.GetUserOrders(ctx, user, filter)
 		if err != nil {
 			logger.Error("failed to get user orders",
 				zap.Int64("user_id", user.ID),
@@ -35,28 +54,14 @@ func GetUserOrders(
 			return
 		}
 
-		if len(orders) == 0 {
+		writePageHeaders(res, page.Total, page.Next)
+
+		if len(page.Orders) == 0 {
 			responser.WriteNoContent(ctx, res)
 			return
 		}
 
-		responseData := []responses.Order{}
-
-		for _, order := range orders {
-			orderData := responses.Order{
-				OrderNumber: order.OrderNumber,
-				Status:      string(order.Status),
-				UploadedAt:  order.UploadedAt.Format(time.RFC3339),
-			}
-
-			if ordersService.HasProcessedStatus(order) {
-				orderData.Accrual, _ = order.Accrual.Float64()
-			}
-
-			responseData = append(responseData, orderData)
-		}
-
-		rawResponseData, err := json.Marshal(responseData)
+		rawResponseData, err := json.Marshal(newOrdersResponse(page.Orders, ordersService, responses.MoneyFormatFromContext(ctx)))
 		if err != nil {
 			logger.Error("failed to marshal user orders",
 				zap.Int64("user_id", user.ID),
@@ -70,3 +75,67 @@ func GetUserOrders(
 		res.Write(rawResponseData)
 	}
 }
+
+// parseOrdersFilter читает параметры limit, cursor, status, from и to
+func parseOrdersFilter(req *http.Request) (models.OrdersFilter, error) {
+	var filter models.OrdersFilter
+	var err error
+
+	filter.Limit, err = parsePageLimit(req, ordersDefaultLimit, ordersMaxLimit)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.After, err = parseCursor(req)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.From, err = parseTime(req, "from")
+	if err != nil {
+		return filter, err
+	}
+
+	filter.To, err = parseTime(req, "to")
+	if err != nil {
+		return filter, err
+	}
+
+	for _, rawStatus := range parseList(req, "status") {
+		status := types.OrderStatus(strings.ToUpper(rawStatus))
+
+		switch status {
+		case types.OrderStatusNew, types.OrderStatusProcessing, types.OrderStatusInvalid, types.OrderStatusProcessed:
+			filter.Statuses = append(filter.Statuses, status)
+		default:
+			return filter, errInvalidOrderStatus
+		}
+	}
+
+	return filter, nil
+}
+
+func newOrdersResponse(orders []models.Order, ordersService OrdersService, moneyFormat responses.MoneyFormat) []responses.Order {
+	responseData := []responses.Order{}
+
+	for _, order := range orders {
+		responseData = append(responseData, newOrderResponse(order, ordersService, moneyFormat))
+	}
+
+	return responseData
+}
+
+func newOrderResponse(order models.Order, ordersService OrdersService, moneyFormat responses.MoneyFormat) responses.Order {
+	orderData := responses.Order{
+		OrderNumber: order.OrderNumber,
+		Status:      string(order.Status),
+		UploadedAt:  order.UploadedAt.Format(time.RFC3339),
+	}
+
+	if ordersService.HasProcessedStatus(order) {
+		accrual := responses.NewMoney(order.Accrual, moneyFormat)
+		orderData.Accrual = &accrual
+	}
+
+	return orderData
+}
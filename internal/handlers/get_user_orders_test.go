@@ -60,9 +60,13 @@ func TestGetUserOrders(t *testing.T) {
 
 		accrualer := mocks.NewMockAccrualer(ctrl)
 		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		// без limit и cursor отдаются все заказы, как в спецификации
 		ordersStorage.EXPECT().
-			GetUserOrders(gomock.Any(), gomock.Any()).
+			GetUserOrders(gomock.Any(), user, models.OrdersFilter{}).
 			Return(orders, nil)
+		ordersStorage.EXPECT().
+			CountUserOrders(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(3, nil)
 		ordersService := services.NewOrdersService(ordersStorage, accrualer, logger)
 
 		handler := GetUserOrders(responser, userReceiver, logger, ordersService)
@@ -72,6 +76,8 @@ func TestGetUserOrders(t *testing.T) {
 			Get("/api/user/orders").
 			Expect(t).
 			Status(http.StatusOK).
+			Header("X-Total-Count", "3").
+			HeaderNotPresent("X-Next-Cursor").
 			Body(`[
                 {
                     "number": "3",
@@ -103,8 +109,11 @@ func TestGetUserOrders(t *testing.T) {
 		accrualer := mocks.NewMockAccrualer(ctrl)
 		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
 		ordersStorage.EXPECT().
-			GetUserOrders(gomock.Any(), gomock.Any()).
+			GetUserOrders(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return([]models.Order{}, nil)
+		ordersStorage.EXPECT().
+			CountUserOrders(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(0, nil)
 		ordersService := services.NewOrdersService(ordersStorage, accrualer, logger)
 
 		handler := GetUserOrders(responser, userReceiver, logger, ordersService)
@@ -118,4 +127,115 @@ func TestGetUserOrders(t *testing.T) {
 			End()
 	})
 
+	t.Run("orders paginated and filtered", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		uploadedAt := time.Date(2020, 12, 10, 12, 15, 45, 0, time.UTC)
+
+		orders := []models.Order{
+			{
+				OrderNumber: "3",
+				Status:      types.OrderStatusProcessed,
+				UploadedAt:  uploadedAt,
+				Accrual:     decimal.NewFromInt(1),
+			},
+			{
+				OrderNumber: "2",
+				Status:      types.OrderStatusProcessed,
+				UploadedAt:  uploadedAt,
+				Accrual:     decimal.NewFromInt(2),
+			},
+			{
+				OrderNumber: "1",
+				Status:      types.OrderStatusProcessed,
+				UploadedAt:  uploadedAt,
+				Accrual:     decimal.NewFromInt(3),
+			},
+		}
+
+		cursor := models.PageCursor{
+			At:  time.Date(2020, 12, 11, 0, 0, 0, 0, time.UTC),
+			Key: "10",
+		}
+
+		// сервис запрашивает на один заказ больше, чтобы понять, есть ли следующая страница
+		expectedFilter := models.OrdersFilter{
+			Statuses: []types.OrderStatus{types.OrderStatusProcessed, types.OrderStatusInvalid},
+			From:     time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC),
+			To:       time.Date(2020, 12, 31, 21, 0, 0, 0, time.UTC),
+			After:    cursor,
+			Limit:    3,
+		}
+
+		accrualer := mocks.NewMockAccrualer(ctrl)
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().
+			GetUserOrders(gomock.Any(), user, expectedFilter).
+			Return(orders, nil)
+		ordersStorage.EXPECT().
+			CountUserOrders(gomock.Any(), user, gomock.Any()).
+			Return(42, nil)
+		ordersService := services.NewOrdersService(ordersStorage, accrualer, logger)
+
+		handler := GetUserOrders(responser, userReceiver, logger, ordersService)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Get("/api/user/orders").
+			Query("limit", "2").
+			Query("cursor", encodeCursor(cursor)).
+			Query("status", "processed,INVALID").
+			Query("from", "2020-12-01T00:00:00Z").
+			Query("to", "2021-01-01T00:00:00+03:00").
+			Expect(t).
+			Status(http.StatusOK).
+			Header("X-Total-Count", "42").
+			Header("X-Next-Cursor", encodeCursor(models.PageCursor{At: uploadedAt, Key: "2"})).
+			Body(`[
+                {
+                    "number": "3",
+                    "status": "PROCESSED",
+                    "accrual": 1,
+                    "uploaded_at": "2020-12-10T12:15:45Z"
+                },
+                {
+                    "number": "2",
+                    "status": "PROCESSED",
+                    "accrual": 2,
+                    "uploaded_at": "2020-12-10T12:15:45Z"
+                }
+            ]`).
+			End()
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			AnyTimes().
+			Return(user, nil)
+
+		ordersService := services.NewOrdersService(mocks.NewMockOrdersStorage(ctrl), mocks.NewMockAccrualer(ctrl), logger)
+
+		handler := GetUserOrders(responser, userReceiver, logger, ordersService)
+
+		invalidQueries := map[string]string{
+			"status": "DONE",
+			"limit":  "0",
+			"cursor": "abc",
+			"from":   "yesterday",
+		}
+		for name, value := range invalidQueries {
+			apitest.New().
+				HandlerFunc(handler).
+				Get("/api/user/orders").
+				Query(name, value).
+				Expect(t).
+				Status(http.StatusBadRequest).
+				End()
+		}
+	})
 }
@@ -5,11 +5,21 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
 	"github.com/aleksandrpnshkn/gophermart/internal/responses"
 	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
 	"go.uber.org/zap"
 )
 
+const (
+	withdrawalsDefaultLimit = 100
+	withdrawalsMaxLimit     = 1000
+
+	totalSumHeader      = "X-Total-Sum"
+	totalRefundedHeader = "X-Total-Refunded"
+)
+
 func GetWithdrawals(
 	responser *services.Responser,
 	userReceiver UserReceiver,
@@ -26,20 +36,44 @@ func GetWithdrawals(
 			return
 		}
 
-		withdrawals, err := withdrawer.GetWithdrawals(ctx, user)
+		filter, err := parseWithdrawalsFilter(req)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		page, err := withdrawer.GetWithdrawals(ctx, user, filter)
 		if err != nil {
 			logger.Error("failed to get user withdrawals", zap.Error(err))
 			responser.WriteInternalServerError(ctx, res)
 			return
 		}
 
+		// итоги в заголовках, чтобы тело ответа осталось массивом из спецификации
+		moneyFormat := responses.MoneyFormatFromContext(ctx)
+		writePageHeaders(res, page.Summary.Count, page.Next)
+		res.Header().Set(totalSumHeader, responses.NewMoney(page.Summary.Sum, moneyFormat).String())
+		res.Header().Set(totalRefundedHeader, responses.NewMoney(page.Summary.Refunded, moneyFormat).String())
+
+		if len(page.Withdrawals) == 0 {
+			responser.WriteNoContent(ctx, res)
+			return
+		}
+
 		responseData := []responses.Withdraw{}
-		for _, balanceChange := range withdrawals {
-			responseData = append(responseData, responses.Withdraw{
+		for _, balanceChange := range page.Withdrawals {
+			withdraw := responses.Withdraw{
 				OrderNumber: balanceChange.OrderNumber,
-				Sum:         balanceChange.Amount.Abs().InexactFloat64(),
+				Sum:         responses.NewMoney(balanceChange.Amount.Abs(), moneyFormat),
+				Status:      string(types.WithdrawalStatusProcessed),
 				ProcessedAt: balanceChange.ProcessedAt.Format(time.RFC3339),
-			})
+			}
+			if !balanceChange.RefundedAt.IsZero() {
+				withdraw.Status = string(types.WithdrawalStatusRefunded)
+				withdraw.RefundedAt = balanceChange.RefundedAt.Format(time.RFC3339)
+			}
+
+			responseData = append(responseData, withdraw)
 		}
 
 		rawResponseData, err := json.Marshal(responseData)
@@ -56,3 +90,31 @@ func GetWithdrawals(
 		res.Write(rawResponseData)
 	}
 }
+
+// parseWithdrawalsFilter читает параметры limit, cursor, from и to
+func parseWithdrawalsFilter(req *http.Request) (models.WithdrawalsFilter, error) {
+	var filter models.WithdrawalsFilter
+	var err error
+
+	filter.Limit, err = parsePageLimit(req, withdrawalsDefaultLimit, withdrawalsMaxLimit)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.After, err = parseCursor(req)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.From, err = parseTime(req, "from")
+	if err != nil {
+		return filter, err
+	}
+
+	filter.To, err = parseTime(req, "to")
+	if err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
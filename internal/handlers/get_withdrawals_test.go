@@ -5,8 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
 	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
 	"github.com/aleksandrpnshkn/gophermart/internal/services"
 	"github.com/aleksandrpnshkn/gophermart/internal/types"
 	"github.com/shopspring/decimal"
@@ -43,12 +45,26 @@ func TestGetWithdrawals(t *testing.T) {
 				Amount:      decimal.NewFromInt(-500),
 				ProcessedAt: time.Date(2020, 12, 9, 16, 9, 57, 0, loc),
 			},
+			{
+				OrderNumber: "12345678903",
+				UserID:      user.ID,
+				Amount:      decimal.NewFromInt(-100),
+				ProcessedAt: time.Date(2020, 12, 8, 12, 0, 0, 0, loc),
+				RefundedAt:  time.Date(2020, 12, 9, 10, 30, 0, 0, loc),
+			},
 		}
 
 		withdrawer := mocks.NewMockWithdrawer(ctrl)
 		withdrawer.EXPECT().
-			GetWithdrawals(gomock.Any(), user).
-			Return(withdrawals, nil)
+			GetWithdrawals(gomock.Any(), user, models.WithdrawalsFilter{}).
+			Return(models.WithdrawalsPage{
+				Withdrawals: withdrawals,
+				Summary: models.WithdrawalsSummary{
+					Count:    2,
+					Sum:      decimal.NewFromInt(600),
+					Refunded: decimal.NewFromInt(100),
+				},
+			}, nil)
 
 		handler := GetWithdrawals(responser, userReceiver, withdrawer, logger)
 
@@ -57,11 +73,158 @@ func TestGetWithdrawals(t *testing.T) {
 			Post("/api/user/withdrawals").
 			Expect(t).
 			Status(http.StatusOK).
+			Header("X-Total-Count", "2").
+			Header("X-Total-Sum", "600").
+			Header("X-Total-Refunded", "100").
+			HeaderNotPresent("X-Next-Cursor").
 			Body(`[
                 {
                     "order": "2377225624",
                     "sum": 500,
+                    "status": "PROCESSED",
                     "processed_at": "2020-12-09T16:09:57+03:00"
+                },
+                {
+                    "order": "12345678903",
+                    "sum": 100,
+                    "status": "REFUNDED",
+                    "processed_at": "2020-12-08T12:00:00+03:00",
+                    "refunded_at": "2020-12-09T10:30:00+03:00"
+                }
+            ]`).
+			End()
+	})
+	t.Run("withdrawals paginated and filtered", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		processedAt := time.Date(2020, 12, 9, 13, 9, 57, 0, time.UTC)
+
+		withdrawals := []models.BalanceChange{
+			{
+				ID:          "0d9c7a5e-6b1b-4f8e-9d55-0a7f7b0e2c11",
+				OrderNumber: "2377225624",
+				UserID:      user.ID,
+				Amount:      decimal.NewFromInt(-500),
+				ProcessedAt: processedAt,
+			},
+			{
+				ID:          "0b1f5c34-2a3e-4c1d-8f6a-5e9d7c3b2a10",
+				OrderNumber: "12345678903",
+				UserID:      user.ID,
+				Amount:      decimal.NewFromInt(-100),
+				ProcessedAt: processedAt,
+			},
+		}
+
+		expectedFilter := models.WithdrawalsFilter{
+			From:  time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC),
+			To:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			Limit: 2,
+		}
+
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		balanceStorage.EXPECT().
+			GetWithdrawals(gomock.Any(), user, expectedFilter).
+			Return(withdrawals, nil)
+		balanceStorage.EXPECT().
+			GetWithdrawalsSummary(gomock.Any(), user, gomock.Any()).
+			Return(models.WithdrawalsSummary{Count: 5, Sum: decimal.RequireFromString("1234.5")}, nil)
+
+		balancer := services.NewBalancer(mocks.NewMockOrdersService(ctrl), balanceStorage, logger)
+
+		handler := GetWithdrawals(responser, userReceiver, balancer, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Get("/api/user/withdrawals").
+			Query("limit", "1").
+			Query("from", "2020-12-01T00:00:00Z").
+			Query("to", "2021-01-01T00:00:00Z").
+			Expect(t).
+			Status(http.StatusOK).
+			Header("X-Total-Count", "5").
+			Header("X-Total-Sum", "1234.5").
+			Header("X-Total-Refunded", "0").
+			Header("X-Next-Cursor", encodeCursor(models.PageCursor{
+				At:  processedAt,
+				Key: "0d9c7a5e-6b1b-4f8e-9d55-0a7f7b0e2c11",
+			})).
+			Body(`[
+                {
+                    "order": "2377225624",
+                    "sum": 500,
+                    "status": "PROCESSED",
+                    "processed_at": "2020-12-09T13:09:57Z"
+                }
+            ]`).
+			End()
+	})
+	t.Run("no withdrawals", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		withdrawer := mocks.NewMockWithdrawer(ctrl)
+		withdrawer.EXPECT().
+			GetWithdrawals(gomock.Any(), user, models.WithdrawalsFilter{}).
+			Return(models.WithdrawalsPage{Withdrawals: []models.BalanceChange{}}, nil)
+
+		handler := GetWithdrawals(responser, userReceiver, withdrawer, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Get("/api/user/withdrawals").
+			Expect(t).
+			Status(http.StatusNoContent).
+			Header("X-Total-Count", "0").
+			Header("X-Total-Sum", "0").
+			End()
+	})
+	t.Run("summary sum is exact with money as strings", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		withdrawer := mocks.NewMockWithdrawer(ctrl)
+		withdrawer.EXPECT().
+			GetWithdrawals(gomock.Any(), user, models.WithdrawalsFilter{}).
+			Return(models.WithdrawalsPage{
+				Withdrawals: []models.BalanceChange{
+					{
+						OrderNumber: "2377225624",
+						UserID:      user.ID,
+						Amount:      decimal.RequireFromString("-0.1"),
+						ProcessedAt: time.Date(2020, 12, 9, 13, 9, 57, 0, time.UTC),
+					},
+				},
+				Summary: models.WithdrawalsSummary{
+					Count: 2,
+					Sum:   decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2")),
+				},
+			}, nil)
+
+		handler := middlewares.NewMoneyFormatMiddleware(responses.MoneyFormatNumber)(
+			GetWithdrawals(responser, userReceiver, withdrawer, logger),
+		)
+
+		apitest.New().
+			Handler(handler).
+			Get("/api/user/withdrawals").
+			Header("Accept", "application/json; money=string").
+			Expect(t).
+			Status(http.StatusOK).
+			Header("X-Total-Sum", "0.3").
+			Body(`[
+                {
+                    "order": "2377225624",
+                    "sum": "0.1",
+                    "status": "PROCESSED",
+                    "processed_at": "2020-12-09T13:09:57Z"
                 }
             ]`).
 			End()
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+type CircuitStater interface {
+	State() services.CircuitState
+}
+
+// Health отвечает 200, даже если accrual недоступен: заказы дождутся его в очереди,
+// а перезапуск сервиса тут не поможет
+func Health(
+	responser *services.Responser,
+	accrualCircuit CircuitStater,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		accrualState := accrualCircuit.State()
+
+		responseData := responses.Health{
+			Status:  "ok",
+			Accrual: string(accrualState),
+		}
+		if accrualState != services.CircuitStateClosed {
+			responseData.Status = "degraded"
+		}
+
+		rawResponseData, err := json.Marshal(responseData)
+		if err != nil {
+			logger.Error("failed to marshal health", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		res.Header().Set("Cache-Control", "no-store")
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/zap"
+)
+
+func TestHealth(t *testing.T) {
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+
+	t.Run("accrual available", func(t *testing.T) {
+		accrualCircuit := services.NewCircuitBreaker("accrual", 1, time.Minute, logger)
+
+		apitest.New().
+			HandlerFunc(Health(responser, accrualCircuit, logger)).
+			Get("/api/health").
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`{"status": "ok", "accrual": "closed"}`).
+			End()
+	})
+
+	t.Run("accrual circuit open", func(t *testing.T) {
+		accrualCircuit := services.NewCircuitBreaker("accrual", 1, time.Minute, logger)
+		accrualCircuit.Failure()
+
+		apitest.New().
+			HandlerFunc(Health(responser, accrualCircuit, logger)).
+			Get("/api/health").
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`{"status": "degraded", "accrual": "open"}`).
+			End()
+	})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"go.uber.org/zap"
+)
+
+type JWKsGetter interface {
+	JWKs() []responses.JWK
+}
+
+// GetJWKS отдаёт публичные ключи, чтобы другие сервисы могли проверять токены без секрета
+func GetJWKS(
+	responser *services.Responser,
+	jwksGetter JWKsGetter,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		rawResponseData, err := json.Marshal(responses.JWKS{
+			Keys: jwksGetter.JWKs(),
+		})
+		if err != nil {
+			logger.Error("failed to marshal jwks", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		res.Header().Set("Cache-Control", "public, max-age=300")
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
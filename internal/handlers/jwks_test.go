@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"testing"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/steinfletcher/apitest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetJWKS(t *testing.T) {
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+
+	t.Run("shared secret is not published", func(t *testing.T) {
+		handler := GetJWKS(responser, services.NewHMACJwtKeys("secret"), logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Get("/.well-known/jwks.json").
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`{"keys": []}`).
+			End()
+	})
+
+	t.Run("public keys", func(t *testing.T) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		keys, err := services.NewJwtKeys(key)
+		require.NoError(t, err)
+
+		jwk := keys.JWKs()[0]
+
+		apitest.New().
+			HandlerFunc(GetJWKS(responser, keys, logger)).
+			Get("/.well-known/jwks.json").
+			Expect(t).
+			Status(http.StatusOK).
+			Body(`{"keys": [{"kty": "OKP", "kid": "` + jwk.KeyID + `", "use": "sig", "alg": "EdDSA", "crv": "Ed25519", "x": "` + jwk.X + `"}]}`).
+			End()
+	})
+}
@@ -5,26 +5,35 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
 	"github.com/aleksandrpnshkn/gophermart/internal/requests"
 	"github.com/aleksandrpnshkn/gophermart/internal/responses"
 	"github.com/aleksandrpnshkn/gophermart/internal/services"
-	"github.com/aleksandrpnshkn/gophermart/internal/types"
 	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 )
 
+type LoginThrottler interface {
+	BlockedFor(ctx context.Context, login string, ip string) (time.Duration, error)
+	RegisterFailure(ctx context.Context, login string, ip string) error
+	RegisterSuccess(ctx context.Context, login string) error
+}
+
 type UserLoginer interface {
-	LoginUser(ctx context.Context, login string, password string) (models.User, types.RawToken, error)
+	LoginUser(ctx context.Context, login string, password string) (models.User, models.AuthTokens, error)
 }
 
 func Login(
 	responser *services.Responser,
 	validate *validator.Validate,
+	authCookies *middlewares.AuthCookies,
 	userLoginer UserLoginer,
+	loginThrottler LoginThrottler,
 	logger *zap.Logger,
 ) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
@@ -37,7 +46,7 @@ func Login(
 		}
 		defer req.Body.Close()
 
-		var requestData requests.Register
+		var requestData requests.Login
 		err = json.Unmarshal(rawRequestData, &requestData)
 		if err != nil {
 			responser.WriteBadRequestError(ctx, res)
@@ -50,19 +59,55 @@ func Login(
 			return
 		}
 
-		_, rawToken, err := userLoginer.LoginUser(ctx, requestData.Login, requestData.Password)
+		ip := clientIP(req)
+
+		blockedFor, err := loginThrottler.BlockedFor(ctx, requestData.Login, ip)
+		if err != nil {
+			logger.Error("failed to check login attempts", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+		if blockedFor > 0 {
+			responser.WriteTooManyRequestsError(ctx, res, blockedFor)
+			return
+		}
+
+		_, tokens, err := userLoginer.LoginUser(ctx, requestData.Login, requestData.Password)
 		if err != nil {
 			if errors.Is(err, services.ErrBadCredentials) {
+				err = loginThrottler.RegisterFailure(ctx, requestData.Login, ip)
+				if err != nil {
+					logger.Error("failed to register login failure", zap.Error(err))
+				}
+
 				responser.WriteUnauthorizedError(ctx, res)
 				return
 			}
+			if errors.Is(err, services.ErrUserLocked) {
+				responser.WriteForbiddenError(ctx, res)
+				return
+			}
 
 			logger.Error("failed to login user", zap.Error(err))
 			responser.WriteInternalServerError(ctx, res)
 			return
 		}
 
-		middlewares.SetAuthCookie(res, rawToken)
+		err = loginThrottler.RegisterSuccess(ctx, requestData.Login)
+		if err != nil {
+			logger.Error("failed to reset login failures", zap.Error(err))
+		}
+
+		authCookies.Set(res, tokens)
+
+		if requestData.ReturnTokens {
+			err = writeAuthTokens(res, tokens)
+			if err != nil {
+				logger.Error("failed to write auth tokens", zap.Error(err))
+				responser.WriteInternalServerError(ctx, res)
+			}
+			return
+		}
 
 		rawResponseData, _ := responses.EncodeOkResponse()
 
@@ -70,3 +115,12 @@ func Login(
 		res.Write(rawResponseData)
 	}
 }
+
+// clientIP адрес клиента без порта. За прокси RemoteAddr должен выставлять middleware RealIP.
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
 	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
@@ -19,14 +20,15 @@ func TestLogin(t *testing.T) {
 	defer ctrl.Finish()
 
 	uni := services.NewAppUni()
-	validate := services.NewValidate(uni)
+	validate := services.NewValidate(uni, services.PasswordPolicy{MinLength: 8, MinCharClasses: 2})
 	responser := services.NewResponser(uni)
+	authCookies := middlewares.NewAuthCookies(middlewares.CookieSettings{})
 	logger := zap.NewExample()
 
 	t.Run("invalid data format", func(t *testing.T) {
 		userLoginer := mocks.NewMockUserLoginer(ctrl)
 
-		handler := Login(responser, validate, userLoginer, logger)
+		handler := Login(responser, validate, authCookies, userLoginer, mocks.NewMockLoginThrottler(ctrl), logger)
 
 		apitest.New().
 			HandlerFunc(handler).
@@ -39,7 +41,7 @@ func TestLogin(t *testing.T) {
 	t.Run("invalid data", func(t *testing.T) {
 		userLoginer := mocks.NewMockUserLoginer(ctrl)
 
-		handler := Login(responser, validate, userLoginer, logger)
+		handler := Login(responser, validate, authCookies, userLoginer, mocks.NewMockLoginThrottler(ctrl), logger)
 
 		apitest.New().
 			HandlerFunc(handler).
@@ -69,14 +71,23 @@ func TestLogin(t *testing.T) {
 			Login: "admin",
 			Hash:  types.PasswordHash("blablahash"),
 		}
-		rawToken := types.RawToken("token")
+		tokens := models.AuthTokens{
+			AccessToken:           types.RawToken("token"),
+			AccessTokenExpiresAt:  time.Now().Add(time.Minute),
+			RefreshToken:          types.RawToken("refresh"),
+			RefreshTokenExpiresAt: time.Now().Add(time.Hour),
+		}
 
 		userLoginer := mocks.NewMockUserLoginer(ctrl)
 		userLoginer.EXPECT().
 			LoginUser(gomock.Any(), "admin", "secret").
-			Return(existedUser, rawToken, nil)
+			Return(existedUser, tokens, nil)
+
+		loginThrottler := mocks.NewMockLoginThrottler(ctrl)
+		loginThrottler.EXPECT().BlockedFor(gomock.Any(), "admin", gomock.Any()).Return(time.Duration(0), nil)
+		loginThrottler.EXPECT().RegisterSuccess(gomock.Any(), "admin").Return(nil)
 
-		handler := Login(responser, validate, userLoginer, logger)
+		handler := Login(responser, validate, authCookies, userLoginer, loginThrottler, logger)
 
 		apitest.New().
 			HandlerFunc(handler).
@@ -88,7 +99,43 @@ func TestLogin(t *testing.T) {
 			Expect(t).
 			Status(http.StatusOK).
 			CookiePresent(middlewares.AuthCookieName).
-			Cookie(middlewares.AuthCookieName, string(rawToken)).
+			Cookie(middlewares.AuthCookieName, string(tokens.AccessToken)).
+			Cookie(middlewares.RefreshCookieName, string(tokens.RefreshToken)).
+			End()
+	})
+
+	t.Run("tokens returned in body", func(t *testing.T) {
+		tokens := models.AuthTokens{
+			AccessToken:           types.RawToken("token"),
+			AccessTokenExpiresAt:  time.Now().Add(time.Minute),
+			RefreshToken:          types.RawToken("refresh"),
+			RefreshTokenExpiresAt: time.Now().Add(time.Hour),
+		}
+
+		userLoginer := mocks.NewMockUserLoginer(ctrl)
+		userLoginer.EXPECT().
+			LoginUser(gomock.Any(), "admin", "secret").
+			Return(models.User{ID: 1, Login: "admin"}, tokens, nil)
+
+		loginThrottler := mocks.NewMockLoginThrottler(ctrl)
+		loginThrottler.EXPECT().BlockedFor(gomock.Any(), "admin", gomock.Any()).Return(time.Duration(0), nil)
+		loginThrottler.EXPECT().RegisterSuccess(gomock.Any(), "admin").Return(nil)
+
+		handler := Login(responser, validate, authCookies, userLoginer, loginThrottler, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/login").
+			Body(`{
+                "login": "admin",
+                "password": "secret",
+                "return_tokens": true
+            }`).
+			Expect(t).
+			Status(http.StatusOK).
+			Header("Cache-Control", "no-store").
+			Assert(assertAuthTokens("token", "refresh")).
+			Cookie(middlewares.AuthCookieName, string(tokens.AccessToken)).
 			End()
 	})
 
@@ -96,11 +143,16 @@ func TestLogin(t *testing.T) {
 		userLoginer := mocks.NewMockUserLoginer(ctrl)
 		userLoginer.EXPECT().
 			LoginUser(gomock.Any(), "admin", "secret").
-			Return(models.User{}, types.RawToken(""), services.ErrBadCredentials)
+			Return(models.User{}, models.AuthTokens{}, services.ErrBadCredentials)
+
+		loginThrottler := mocks.NewMockLoginThrottler(ctrl)
+		loginThrottler.EXPECT().BlockedFor(gomock.Any(), "admin", "192.0.2.1").Return(time.Duration(0), nil)
+		loginThrottler.EXPECT().RegisterFailure(gomock.Any(), "admin", "192.0.2.1").Return(nil)
 
-		handler := Login(responser, validate, userLoginer, logger)
+		handler := Login(responser, validate, authCookies, userLoginer, loginThrottler, logger)
 
 		apitest.New().
+			Intercept(withRemoteAddr("192.0.2.1:52100")).
 			HandlerFunc(handler).
 			Post("/api/user/register").
 			Body(`{
@@ -112,4 +164,33 @@ func TestLogin(t *testing.T) {
 			CookieNotPresent(middlewares.AuthCookieName).
 			End()
 	})
+
+	t.Run("too many failed attempts", func(t *testing.T) {
+		loginThrottler := mocks.NewMockLoginThrottler(ctrl)
+		loginThrottler.EXPECT().
+			BlockedFor(gomock.Any(), "admin", "192.0.2.1").
+			Return(1500*time.Millisecond, nil)
+
+		handler := Login(responser, validate, authCookies, mocks.NewMockUserLoginer(ctrl), loginThrottler, logger)
+
+		apitest.New().
+			Intercept(withRemoteAddr("192.0.2.1:52100")).
+			HandlerFunc(handler).
+			Post("/api/user/login").
+			Body(`{
+                "login": "admin",
+                "password": "secret"
+            }`).
+			Expect(t).
+			Status(http.StatusTooManyRequests).
+			Header("Retry-After", "2").
+			CookieNotPresent(middlewares.AuthCookieName).
+			End()
+	})
+}
+
+func withRemoteAddr(addr string) func(*http.Request) {
+	return func(req *http.Request) {
+		req.RemoteAddr = addr
+	}
 }
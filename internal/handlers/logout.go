@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"go.uber.org/zap"
+)
+
+type Logouter interface {
+	Logout(ctx context.Context, accessToken types.RawToken, refreshToken types.RawToken) error
+}
+
+// Logout не требует действующего access токена,
+// чтобы из сессии можно было выйти и после его истечения
+func Logout(
+	responser *services.Responser,
+	authCookies *middlewares.AuthCookies,
+	logouter Logouter,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		accessToken, _ := middlewares.RequestToken(req)
+
+		refreshToken, _, err := requestRefreshToken(req)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
+		err = logouter.Logout(ctx, accessToken, refreshToken)
+		if err != nil {
+			logger.Error("failed to logout", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		authCookies.Clear(res)
+
+		rawResponseData, _ := responses.EncodeOkResponse()
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
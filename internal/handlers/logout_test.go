@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestLogout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	authCookies := middlewares.NewAuthCookies(middlewares.CookieSettings{})
+	logger := zap.NewExample()
+
+	t.Run("session revoked", func(t *testing.T) {
+		logouter := mocks.NewMockLogouter(ctrl)
+		logouter.EXPECT().
+			Logout(gomock.Any(), types.RawToken("access"), types.RawToken("refresh")).
+			Return(nil)
+
+		handler := Logout(responser, authCookies, logouter, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/logout").
+			Cookie(middlewares.AuthCookieName, "access").
+			Cookie(middlewares.RefreshCookieName, "refresh").
+			Expect(t).
+			Status(http.StatusOK).
+			Cookie(middlewares.AuthCookieName, "").
+			Cookie(middlewares.RefreshCookieName, "").
+			End()
+	})
+
+	t.Run("no cookies", func(t *testing.T) {
+		logouter := mocks.NewMockLogouter(ctrl)
+		logouter.EXPECT().
+			Logout(gomock.Any(), types.RawToken(""), types.RawToken("")).
+			Return(nil)
+
+		handler := Logout(responser, authCookies, logouter, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/logout").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		logouter := mocks.NewMockLogouter(ctrl)
+		logouter.EXPECT().
+			Logout(gomock.Any(), types.RawToken(""), types.RawToken("refresh")).
+			Return(errors.New("db is down"))
+
+		handler := Logout(responser, authCookies, logouter, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/logout").
+			Cookie(middlewares.RefreshCookieName, "refresh").
+			Expect(t).
+			Status(http.StatusInternalServerError).
+			End()
+	})
+}
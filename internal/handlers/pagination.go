@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+)
+
+const (
+	totalCountHeader = "X-Total-Count"
+	nextCursorHeader = "X-Next-Cursor"
+)
+
+var (
+	errInvalidLimit  = errors.New("invalid limit")
+	errInvalidCursor = errors.New("invalid cursor")
+	errInvalidTime   = errors.New("invalid time")
+)
+
+// parseLimit читает размер страницы из query-параметра limit
+func parseLimit(req *http.Request, defaultLimit int, maxLimit int) (int, error) {
+	rawLimit := req.URL.Query().Get("limit")
+	if rawLimit == "" {
+		return defaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil || limit < 1 || limit > maxLimit {
+		return 0, errInvalidLimit
+	}
+
+	return limit, nil
+}
+
+// parsePageLimit как parseLimit, но без параметров limit и cursor возвращает 0, то есть весь список:
+// клиенты, которые работают по спецификации, о постраничной выдаче не знают
+func parsePageLimit(req *http.Request, defaultLimit int, maxLimit int) (int, error) {
+	query := req.URL.Query()
+	if query.Get("limit") == "" && query.Get("cursor") == "" {
+		return 0, nil
+	}
+
+	return parseLimit(req, defaultLimit, maxLimit)
+}
+
+type cursorData struct {
+	At  time.Time `json:"at"`
+	Key string    `json:"key"`
+}
+
+// parseCursor читает непрозрачный курсор из query-параметра cursor
+func parseCursor(req *http.Request) (models.PageCursor, error) {
+	rawCursor := req.URL.Query().Get("cursor")
+	if rawCursor == "" {
+		return models.PageCursor{}, nil
+	}
+
+	rawData, err := base64.RawURLEncoding.DecodeString(rawCursor)
+	if err != nil {
+		return models.PageCursor{}, errInvalidCursor
+	}
+
+	var data cursorData
+	err = json.Unmarshal(rawData, &data)
+	if err != nil || data.At.IsZero() {
+		return models.PageCursor{}, errInvalidCursor
+	}
+
+	return models.PageCursor{
+		At:  data.At,
+		Key: data.Key,
+	}, nil
+}
+
+func encodeCursor(cursor models.PageCursor) string {
+	rawData, _ := json.Marshal(cursorData{
+		At:  cursor.At,
+		Key: cursor.Key,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(rawData)
+}
+
+// parseTime читает время в формате RFC3339 или дату YYYY-MM-DD из query-параметра
+func parseTime(req *http.Request, name string) (time.Time, error) {
+	rawTime := req.URL.Query().Get(name)
+	if rawTime == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, rawTime)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, rawTime)
+	}
+	if err != nil {
+		return time.Time{}, errInvalidTime
+	}
+
+	// в ответах время отдаётся в UTC, фильтры приводим к нему же
+	return t.UTC(), nil
+}
+
+// parseList читает значения из повторяющегося или перечисленного через запятую query-параметра
+func parseList(req *http.Request, name string) []string {
+	values := []string{}
+
+	for _, rawValue := range req.URL.Query()[name] {
+		for _, value := range strings.Split(rawValue, ",") {
+			value = strings.TrimSpace(value)
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
+
+func writePageHeaders(res http.ResponseWriter, total int, next models.PageCursor) {
+	res.Header().Set(totalCountHeader, strconv.Itoa(total))
+
+	if !next.IsZero() {
+		res.Header().Set(nextCursorHeader, encodeCursor(next))
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/requests"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"go.uber.org/zap"
+)
+
+type TokenRefresher interface {
+	RefreshTokens(ctx context.Context, refreshToken types.RawToken) (models.AuthTokens, error)
+}
+
+func RefreshTokens(
+	responser *services.Responser,
+	authCookies *middlewares.AuthCookies,
+	tokenRefresher TokenRefresher,
+	logger *zap.Logger,
+) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		refreshToken, fromBody, err := requestRefreshToken(req)
+		if err != nil {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+		if refreshToken == "" {
+			responser.WriteUnauthorizedError(ctx, res)
+			return
+		}
+
+		tokens, err := tokenRefresher.RefreshTokens(ctx, refreshToken)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidToken) {
+				authCookies.Clear(res)
+				responser.WriteUnauthorizedError(ctx, res)
+				return
+			}
+			if errors.Is(err, services.ErrUserLocked) {
+				authCookies.Clear(res)
+				responser.WriteForbiddenError(ctx, res)
+				return
+			}
+
+			logger.Error("failed to refresh tokens", zap.Error(err))
+			responser.WriteInternalServerError(ctx, res)
+			return
+		}
+
+		// Клиент, приславший токен в теле, куками не пользуется
+		if fromBody {
+			err = writeAuthTokens(res, tokens)
+			if err != nil {
+				logger.Error("failed to write auth tokens", zap.Error(err))
+				responser.WriteInternalServerError(ctx, res)
+			}
+			return
+		}
+
+		authCookies.Set(res, tokens)
+
+		rawResponseData, _ := responses.EncodeOkResponse()
+
+		res.WriteHeader(http.StatusOK)
+		res.Write(rawResponseData)
+	}
+}
+
+// requestRefreshToken берёт refresh токен из тела запроса, а если тело пустое - из куки
+func requestRefreshToken(req *http.Request) (types.RawToken, bool, error) {
+	rawRequestData, err := io.ReadAll(req.Body)
+	if err != nil {
+		return types.RawToken(""), false, err
+	}
+	defer req.Body.Close()
+
+	if len(bytes.TrimSpace(rawRequestData)) > 0 {
+		var requestData requests.RefreshTokens
+		err = json.Unmarshal(rawRequestData, &requestData)
+		if err != nil {
+			return types.RawToken(""), false, err
+		}
+
+		if requestData.RefreshToken != "" {
+			return types.RawToken(requestData.RefreshToken), true, nil
+		}
+	}
+
+	refreshCookie, err := req.Cookie(middlewares.RefreshCookieName)
+	if err != nil {
+		return types.RawToken(""), false, nil
+	}
+
+	return types.RawToken(refreshCookie.Value), false, nil
+}
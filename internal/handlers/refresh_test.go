@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestRefreshTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	authCookies := middlewares.NewAuthCookies(middlewares.CookieSettings{})
+	logger := zap.NewExample()
+
+	t.Run("no refresh token", func(t *testing.T) {
+		handler := RefreshTokens(responser, authCookies, mocks.NewMockTokenRefresher(ctrl), logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/refresh").
+			Expect(t).
+			Status(http.StatusUnauthorized).
+			End()
+	})
+
+	t.Run("tokens refreshed", func(t *testing.T) {
+		tokens := models.AuthTokens{
+			AccessToken:           types.RawToken("new-access"),
+			AccessTokenExpiresAt:  time.Now().Add(time.Minute),
+			RefreshToken:          types.RawToken("new-refresh"),
+			RefreshTokenExpiresAt: time.Now().Add(time.Hour),
+		}
+
+		tokenRefresher := mocks.NewMockTokenRefresher(ctrl)
+		tokenRefresher.EXPECT().
+			RefreshTokens(gomock.Any(), types.RawToken("old-refresh")).
+			Return(tokens, nil)
+
+		handler := RefreshTokens(responser, authCookies, tokenRefresher, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/refresh").
+			Cookie(middlewares.RefreshCookieName, "old-refresh").
+			Expect(t).
+			Status(http.StatusOK).
+			Cookie(middlewares.AuthCookieName, string(tokens.AccessToken)).
+			Cookie(middlewares.RefreshCookieName, string(tokens.RefreshToken)).
+			End()
+	})
+
+	t.Run("refresh token sent in body", func(t *testing.T) {
+		tokens := models.AuthTokens{
+			AccessToken:           types.RawToken("new-access"),
+			AccessTokenExpiresAt:  time.Now().Add(time.Minute),
+			RefreshToken:          types.RawToken("new-refresh"),
+			RefreshTokenExpiresAt: time.Now().Add(time.Hour),
+		}
+
+		tokenRefresher := mocks.NewMockTokenRefresher(ctrl)
+		tokenRefresher.EXPECT().
+			RefreshTokens(gomock.Any(), types.RawToken("old-refresh")).
+			Return(tokens, nil)
+
+		handler := RefreshTokens(responser, authCookies, tokenRefresher, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/refresh").
+			Body(`{"refresh_token": "old-refresh"}`).
+			Expect(t).
+			Status(http.StatusOK).
+			Assert(assertAuthTokens("new-access", "new-refresh")).
+			CookieNotPresent(middlewares.AuthCookieName).
+			End()
+	})
+
+	t.Run("invalid refresh token", func(t *testing.T) {
+		tokenRefresher := mocks.NewMockTokenRefresher(ctrl)
+		tokenRefresher.EXPECT().
+			RefreshTokens(gomock.Any(), types.RawToken("reused")).
+			Return(models.AuthTokens{}, services.ErrInvalidToken)
+
+		handler := RefreshTokens(responser, authCookies, tokenRefresher, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/refresh").
+			Cookie(middlewares.RefreshCookieName, "reused").
+			Expect(t).
+			Status(http.StatusUnauthorized).
+			Cookie(middlewares.RefreshCookieName, "").
+			End()
+	})
+
+	t.Run("user locked", func(t *testing.T) {
+		tokenRefresher := mocks.NewMockTokenRefresher(ctrl)
+		tokenRefresher.EXPECT().
+			RefreshTokens(gomock.Any(), types.RawToken("refresh")).
+			Return(models.AuthTokens{}, services.ErrUserLocked)
+
+		handler := RefreshTokens(responser, authCookies, tokenRefresher, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/refresh").
+			Cookie(middlewares.RefreshCookieName, "refresh").
+			Expect(t).
+			Status(http.StatusForbidden).
+			End()
+	})
+}
+
+func assertAuthTokens(accessToken string, refreshToken string) func(*http.Response, *http.Request) error {
+	return func(res *http.Response, req *http.Request) error {
+		var tokens responses.AuthTokens
+		err := json.NewDecoder(res.Body).Decode(&tokens)
+		if err != nil {
+			return err
+		}
+
+		if tokens.AccessToken != accessToken || tokens.RefreshToken != refreshToken || tokens.TokenType != "Bearer" {
+			return fmt.Errorf("unexpected tokens: %+v", tokens)
+		}
+		if tokens.ExpiresIn <= 0 || tokens.RefreshTokenExpiresIn <= 0 {
+			return fmt.Errorf("unexpected tokens lifetime: %+v", tokens)
+		}
+
+		return nil
+	}
+}
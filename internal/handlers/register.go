@@ -12,18 +12,18 @@ import (
 	"github.com/aleksandrpnshkn/gophermart/internal/requests"
 	"github.com/aleksandrpnshkn/gophermart/internal/responses"
 	"github.com/aleksandrpnshkn/gophermart/internal/services"
-	"github.com/aleksandrpnshkn/gophermart/internal/types"
 	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 )
 
 type UserRegisterer interface {
-	RegisterUser(ctx context.Context, login string, password string) (models.User, types.RawToken, error)
+	RegisterUser(ctx context.Context, login string, password string) (models.User, models.AuthTokens, error)
 }
 
 func Register(
 	responser *services.Responser,
 	validate *validator.Validate,
+	authCookies *middlewares.AuthCookies,
 	userRegisterer UserRegisterer,
 	logger *zap.Logger,
 ) http.HandlerFunc {
@@ -50,7 +50,7 @@ func Register(
 			return
 		}
 
-		_, token, err := userRegisterer.RegisterUser(ctx, requestData.Login, requestData.Password)
+		_, tokens, err := userRegisterer.RegisterUser(ctx, requestData.Login, requestData.Password)
 		if err != nil {
 			if errors.Is(err, services.ErrLoginAlreadyExists) {
 				res.WriteHeader(http.StatusConflict)
@@ -62,7 +62,16 @@ func Register(
 			return
 		}
 
-		middlewares.SetAuthCookie(res, token)
+		authCookies.Set(res, tokens)
+
+		if requestData.ReturnTokens {
+			err = writeAuthTokens(res, tokens)
+			if err != nil {
+				logger.Error("failed to write auth tokens", zap.Error(err))
+				responser.WriteInternalServerError(ctx, res)
+			}
+			return
+		}
 
 		res.WriteHeader(http.StatusOK)
 
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/middlewares"
 	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
@@ -19,14 +20,15 @@ func TestRegister(t *testing.T) {
 	defer ctrl.Finish()
 
 	uni := services.NewAppUni()
-	validate := services.NewValidate(uni)
+	validate := services.NewValidate(uni, services.PasswordPolicy{MinLength: 6, MinCharClasses: 1})
 	responser := services.NewResponser(uni)
+	authCookies := middlewares.NewAuthCookies(middlewares.CookieSettings{})
 	logger := zap.NewExample()
 
 	t.Run("invalid data format", func(t *testing.T) {
 		userRegisterer := mocks.NewMockUserRegisterer(ctrl)
 
-		handler := Register(responser, validate, userRegisterer, logger)
+		handler := Register(responser, validate, authCookies, userRegisterer, logger)
 
 		apitest.New().
 			HandlerFunc(handler).
@@ -39,7 +41,7 @@ func TestRegister(t *testing.T) {
 	t.Run("invalid data", func(t *testing.T) {
 		userRegisterer := mocks.NewMockUserRegisterer(ctrl)
 
-		handler := Register(responser, validate, userRegisterer, logger)
+		handler := Register(responser, validate, authCookies, userRegisterer, logger)
 
 		apitest.New().
 			HandlerFunc(handler).
@@ -63,20 +65,53 @@ func TestRegister(t *testing.T) {
 			End()
 	})
 
+	t.Run("short password", func(t *testing.T) {
+		userRegisterer := mocks.NewMockUserRegisterer(ctrl)
+
+		handler := Register(responser, validate, authCookies, userRegisterer, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/register").
+			Body(`{
+            "login": "admin",
+            "password": "short"
+        }`).
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			Body(`{
+		    "error": {
+		        "message": "invalid data",
+		        "invalid_fields": [
+		            {
+		                "field": "password",
+		                "message": "password must be from 6 characters up to 72 bytes"
+		            }
+		        ]
+		    }
+		}`).
+			End()
+	})
+
 	t.Run("user registered", func(t *testing.T) {
 		user := models.User{
 			ID:    1,
 			Login: "admin",
 			Hash:  types.PasswordHash("blablahash"),
 		}
-		rawToken := types.RawToken("token")
+		tokens := models.AuthTokens{
+			AccessToken:           types.RawToken("token"),
+			AccessTokenExpiresAt:  time.Now().Add(time.Minute),
+			RefreshToken:          types.RawToken("refresh"),
+			RefreshTokenExpiresAt: time.Now().Add(time.Hour),
+		}
 
 		userRegisterer := mocks.NewMockUserRegisterer(ctrl)
 		userRegisterer.EXPECT().
 			RegisterUser(gomock.Any(), "admin", "secret").
-			Return(user, rawToken, nil)
+			Return(user, tokens, nil)
 
-		handler := Register(responser, validate, userRegisterer, logger)
+		handler := Register(responser, validate, authCookies, userRegisterer, logger)
 
 		apitest.New().
 			HandlerFunc(handler).
@@ -88,7 +123,8 @@ func TestRegister(t *testing.T) {
 			Expect(t).
 			Status(http.StatusOK).
 			CookiePresent(middlewares.AuthCookieName).
-			Cookie(middlewares.AuthCookieName, string(rawToken)).
+			Cookie(middlewares.AuthCookieName, string(tokens.AccessToken)).
+			Cookie(middlewares.RefreshCookieName, string(tokens.RefreshToken)).
 			End()
 	})
 
@@ -96,9 +132,9 @@ func TestRegister(t *testing.T) {
 		userRegisterer := mocks.NewMockUserRegisterer(ctrl)
 		userRegisterer.EXPECT().
 			RegisterUser(gomock.Any(), "admin", "secret").
-			Return(models.User{}, types.RawToken(""), services.ErrLoginAlreadyExists)
+			Return(models.User{}, models.AuthTokens{}, services.ErrLoginAlreadyExists)
 
-		handler := Register(responser, validate, userRegisterer, logger)
+		handler := Register(responser, validate, authCookies, userRegisterer, logger)
 
 		apitest.New().
 			HandlerFunc(handler).
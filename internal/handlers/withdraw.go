@@ -13,6 +13,11 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	idempotencyKeyMaxLength = 100
+)
+
 func Withdraw(
 	responser *services.Responser,
 	validate *validator.Validate,
@@ -30,6 +35,12 @@ func Withdraw(
 			return
 		}
 
+		idempotencyKey := req.Header.Get(idempotencyKeyHeader)
+		if len(idempotencyKey) > idempotencyKeyMaxLength {
+			responser.WriteBadRequestError(ctx, res)
+			return
+		}
+
 		rawRequestData, err := io.ReadAll(req.Body)
 		if err != nil {
 			responser.WriteBadRequestError(ctx, res)
@@ -50,12 +61,20 @@ func Withdraw(
 			return
 		}
 
-		err = withdrawer.Withdraw(ctx, requestData.OrderNumber, requestData.Amount, user)
+		err = withdrawer.Withdraw(ctx, requestData.OrderNumber, requestData.Amount, user, idempotencyKey)
 		if err != nil {
 			if errors.Is(err, services.ErrBalanceNotEnoughFunds) {
 				res.WriteHeader(http.StatusPaymentRequired)
 				return
 			}
+			if errors.Is(err, services.ErrWithdrawalAlreadyExists) {
+				responser.WriteConflict(ctx, res)
+				return
+			}
+			if errors.Is(err, services.ErrIdempotencyKeyReused) {
+				responser.WriteEmptyValidationError(ctx, res)
+				return
+			}
 
 			logger.Error("failed to withdraw", zap.Error(err))
 			responser.WriteInternalServerError(ctx, res)
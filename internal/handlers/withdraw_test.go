@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
 	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	balancePackage "github.com/aleksandrpnshkn/gophermart/internal/storage/balance"
 	"github.com/aleksandrpnshkn/gophermart/internal/types"
 	"github.com/shopspring/decimal"
 	"github.com/steinfletcher/apitest"
@@ -20,7 +22,7 @@ func TestWithdraw(t *testing.T) {
 
 	uni := services.NewAppUni()
 	responser := services.NewResponser(uni)
-	validate := services.NewValidate(uni)
+	validate := services.NewValidate(uni, services.PasswordPolicy{MinLength: 8, MinCharClasses: 2})
 	logger := zap.NewExample()
 
 	user := models.User{
@@ -57,6 +59,9 @@ func TestWithdraw(t *testing.T) {
 		}
 
 		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		balanceStorage.EXPECT().
+			GetOrderWithdrawal(gomock.Any(), "2377225624").
+			Return(models.BalanceChange{}, balancePackage.ErrWithdrawalNotFound)
 		balanceStorage.EXPECT().
 			GetBalance(gomock.Any(), user).
 			Return(balance, nil)
@@ -80,4 +85,155 @@ func TestWithdraw(t *testing.T) {
 			Status(http.StatusOK).
 			End()
 	})
+	t.Run("retried withdrawal with same idempotency key", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		// повторный запрос не должен ни создавать заказ, ни списывать баллы
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		balanceStorage.EXPECT().
+			GetOrderWithdrawal(gomock.Any(), "2377225624").
+			Return(models.BalanceChange{
+				OrderNumber:    "2377225624",
+				UserID:         user.ID,
+				Amount:         decimal.NewFromInt(-751),
+				IdempotencyKey: "8e03978e-40d5-43e8-bc93-6894a57f9324",
+			}, nil)
+
+		balancer := services.NewBalancer(mocks.NewMockOrdersService(ctrl), balanceStorage, logger)
+
+		handler := Withdraw(responser, validate, userReceiver, balancer, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/balance/withdraw").
+			Header("Idempotency-Key", "8e03978e-40d5-43e8-bc93-6894a57f9324").
+			ContentType("application/json").
+			Body(`{
+                "order": "2377225624",
+                "sum": 751
+            }`).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("order already paid", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		balanceStorage.EXPECT().
+			GetOrderWithdrawal(gomock.Any(), "2377225624").
+			Return(models.BalanceChange{
+				OrderNumber: "2377225624",
+				UserID:      user.ID,
+				Amount:      decimal.NewFromInt(-751),
+			}, nil)
+
+		balancer := services.NewBalancer(mocks.NewMockOrdersService(ctrl), balanceStorage, logger)
+
+		handler := Withdraw(responser, validate, userReceiver, balancer, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/balance/withdraw").
+			ContentType("application/json").
+			Body(`{
+                "order": "2377225624",
+                "sum": 751
+            }`).
+			Expect(t).
+			Status(http.StatusConflict).
+			End()
+	})
+
+	t.Run("idempotency key reused for another order", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		ordersService := mocks.NewMockOrdersService(ctrl)
+		ordersService.EXPECT().
+			Add(gomock.Any(), "2377225624", user).
+			Return(models.Order{OrderNumber: "2377225624", UserID: user.ID}, nil)
+
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		balanceStorage.EXPECT().
+			GetOrderWithdrawal(gomock.Any(), "2377225624").
+			Return(models.BalanceChange{}, balancePackage.ErrWithdrawalNotFound)
+		balanceStorage.EXPECT().
+			GetBalance(gomock.Any(), user).
+			Return(models.Balance{Current: decimal.NewFromInt(1000)}, nil)
+		balanceStorage.EXPECT().
+			Withdraw(gomock.Any(), gomock.Any()).
+			Return(balancePackage.ErrIdempotencyKeyUsed)
+
+		balancer := services.NewBalancer(ordersService, balanceStorage, logger)
+
+		handler := Withdraw(responser, validate, userReceiver, balancer, logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/balance/withdraw").
+			Header("Idempotency-Key", "8e03978e-40d5-43e8-bc93-6894a57f9324").
+			ContentType("application/json").
+			Body(`{
+                "order": "2377225624",
+                "sum": 751
+            }`).
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	})
+	t.Run("exact sum as number and string", func(t *testing.T) {
+		for _, rawSum := range []string{`751.3`, `"751.3"`, `751.30000000000000000001`} {
+			userReceiver := mocks.NewMockUserReceiver(ctrl)
+			userReceiver.EXPECT().
+				FromContext(gomock.Any()).
+				Return(user, nil)
+
+			expectedSum := decimal.RequireFromString(strings.Trim(rawSum, `"`))
+
+			withdrawer := mocks.NewMockWithdrawer(ctrl)
+			withdrawer.EXPECT().
+				Withdraw(gomock.Any(), "2377225624", gomock.Cond(func(sum decimal.Decimal) bool {
+					return sum.Equal(expectedSum)
+				}), user, "").
+				Return(nil)
+
+			handler := Withdraw(responser, validate, userReceiver, withdrawer, logger)
+
+			apitest.New().
+				HandlerFunc(handler).
+				Post("/api/user/balance/withdraw").
+				ContentType("application/json").
+				Body(`{"order": "2377225624", "sum": ` + rawSum + `}`).
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+		}
+	})
+	t.Run("sum below minimum", func(t *testing.T) {
+		userReceiver := mocks.NewMockUserReceiver(ctrl)
+		userReceiver.EXPECT().
+			FromContext(gomock.Any()).
+			Return(user, nil)
+
+		handler := Withdraw(responser, validate, userReceiver, mocks.NewMockWithdrawer(ctrl), logger)
+
+		apitest.New().
+			HandlerFunc(handler).
+			Post("/api/user/balance/withdraw").
+			ContentType("application/json").
+			Body(`{"order": "2377225624", "sum": "0.99999999999999999999"}`).
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	})
 }
@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"go.uber.org/zap"
+)
+
+type UserReceiver interface {
+	FromContext(ctx context.Context) (models.User, error)
+}
+
+// NewAdminMiddleware пропускает только админов, подключать после NewAuthMiddleware
+func NewAdminMiddleware(
+	responser *services.Responser,
+	logger *zap.Logger,
+	userReceiver UserReceiver,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			ctx := req.Context()
+
+			user, err := userReceiver.FromContext(ctx)
+			if err != nil {
+				logger.Error("failed to get user", zap.Error(err))
+				responser.WriteInternalServerError(ctx, res)
+				return
+			}
+
+			if user.Role != types.UserRoleAdmin {
+				responser.WriteForbiddenError(ctx, res)
+				return
+			}
+
+			next.ServeHTTP(res, req)
+		})
+	}
+}
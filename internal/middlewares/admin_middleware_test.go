@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+
+	withUser := func(user models.User, next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			ctx := services.NewUserContext(req.Context(), user)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		})
+	}
+
+	t.Run("admin allowed", func(t *testing.T) {
+		admin := models.User{
+			ID:   1,
+			Role: types.UserRoleAdmin,
+		}
+
+		usersStorage := mocks.NewMockUsersStorage(ctrl)
+		usersStorage.EXPECT().GetByID(gomock.Any(), admin.ID).Return(admin, nil)
+		auther := services.NewAuther(usersStorage, mocks.NewMockSessionsStorage(ctrl), services.NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		handler := withUser(admin, NewAdminMiddleware(responser, logger, auther)(testOkHandler()))
+
+		apitest.New().
+			Handler(handler).
+			Get("/").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("customer forbidden", func(t *testing.T) {
+		customer := models.User{
+			ID:   2,
+			Role: types.UserRoleCustomer,
+		}
+
+		usersStorage := mocks.NewMockUsersStorage(ctrl)
+		usersStorage.EXPECT().GetByID(gomock.Any(), customer.ID).Return(customer, nil)
+		auther := services.NewAuther(usersStorage, mocks.NewMockSessionsStorage(ctrl), services.NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		handler := withUser(customer, NewAdminMiddleware(responser, logger, auther)(testOkHandler()))
+
+		apitest.New().
+			Handler(handler).
+			Get("/").
+			Expect(t).
+			Status(http.StatusForbidden).
+			End()
+	})
+
+	t.Run("user not authenticated", func(t *testing.T) {
+		auther := services.NewAuther(mocks.NewMockUsersStorage(ctrl), mocks.NewMockSessionsStorage(ctrl), services.NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		handler := NewAdminMiddleware(responser, logger, auther)(testOkHandler())
+
+		apitest.New().
+			Handler(handler).
+			Get("/").
+			Expect(t).
+			Status(http.StatusInternalServerError).
+			End()
+	})
+}
@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"go.uber.org/zap"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+type APIKeyParser interface {
+	ParseAPIKey(ctx context.Context, rawKey types.RawToken) (models.User, models.APIKey, error)
+}
+
+// NewScopedAuthMiddleware кроме токена пользователя принимает API ключ партнёра,
+// если у ключа есть scope. Подключается только к тем маршрутам, что доступны партнёрам.
+func NewScopedAuthMiddleware(
+	responser *services.Responser,
+	logger *zap.Logger,
+	tokenParser TokenParser,
+	apiKeyParser APIKeyParser,
+	scope types.APIKeyScope,
+) func(http.Handler) http.Handler {
+	authMiddleware := NewAuthMiddleware(responser, logger, tokenParser)
+
+	return func(next http.Handler) http.Handler {
+		tokenAuth := authMiddleware(next)
+
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			ctx := req.Context()
+
+			rawKey := req.Header.Get(APIKeyHeader)
+			if rawKey == "" {
+				tokenAuth.ServeHTTP(res, req)
+				return
+			}
+
+			user, key, err := apiKeyParser.ParseAPIKey(ctx, types.RawToken(rawKey))
+			if err != nil {
+				if errors.Is(err, services.ErrInvalidAPIKey) {
+					res.WriteHeader(http.StatusUnauthorized)
+					return
+				} else if errors.Is(err, services.ErrUserLocked) {
+					responser.WriteForbiddenError(ctx, res)
+					return
+				} else {
+					logger.Error("failed to parse api key", zap.Error(err))
+					responser.WriteInternalServerError(ctx, res)
+					return
+				}
+			}
+
+			if !key.HasScope(scope) {
+				responser.WriteForbiddenError(ctx, res)
+				return
+			}
+
+			req = req.WithContext(services.NewUserContext(ctx, user))
+
+			next.ServeHTTP(res, req)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package middlewares
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/services"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/steinfletcher/apitest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestScopedAuthMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uni := services.NewAppUni()
+	responser := services.NewResponser(uni)
+	logger := zap.NewExample()
+
+	user := models.User{
+		ID: 123,
+	}
+
+	t.Run("api key with scope", func(t *testing.T) {
+		apiKeyParser := mocks.NewMockAPIKeyParser(ctrl)
+		apiKeyParser.EXPECT().
+			ParseAPIKey(gomock.Any(), types.RawToken("gm_key")).
+			Return(user, models.APIKey{Scopes: []types.APIKeyScope{types.APIKeyScopeOrdersWrite}}, nil)
+
+		handler := NewScopedAuthMiddleware(responser, logger, mocks.NewMockTokenParser(ctrl), apiKeyParser, types.APIKeyScopeOrdersWrite)(testOkHandler())
+
+		apitest.New().
+			Handler(handler).
+			Post("/").
+			Header(APIKeyHeader, "gm_key").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("api key without scope", func(t *testing.T) {
+		apiKeyParser := mocks.NewMockAPIKeyParser(ctrl)
+		apiKeyParser.EXPECT().
+			ParseAPIKey(gomock.Any(), types.RawToken("gm_key")).
+			Return(user, models.APIKey{Scopes: []types.APIKeyScope{types.APIKeyScopeBalanceRead}}, nil)
+
+		handler := NewScopedAuthMiddleware(responser, logger, mocks.NewMockTokenParser(ctrl), apiKeyParser, types.APIKeyScopeOrdersWrite)(testOkHandler())
+
+		apitest.New().
+			Handler(handler).
+			Post("/").
+			Header(APIKeyHeader, "gm_key").
+			Expect(t).
+			Status(http.StatusForbidden).
+			End()
+	})
+
+	t.Run("invalid api key", func(t *testing.T) {
+		apiKeyParser := mocks.NewMockAPIKeyParser(ctrl)
+		apiKeyParser.EXPECT().
+			ParseAPIKey(gomock.Any(), types.RawToken("gm_revoked")).
+			Return(models.User{}, models.APIKey{}, services.ErrInvalidAPIKey)
+
+		handler := NewScopedAuthMiddleware(responser, logger, mocks.NewMockTokenParser(ctrl), apiKeyParser, types.APIKeyScopeOrdersWrite)(testOkHandler())
+
+		apitest.New().
+			Handler(handler).
+			Post("/").
+			Header(APIKeyHeader, "gm_revoked").
+			Expect(t).
+			Status(http.StatusUnauthorized).
+			End()
+	})
+
+	t.Run("user token", func(t *testing.T) {
+		tokenParser := mocks.NewMockTokenParser(ctrl)
+		tokenParser.EXPECT().ParseToken(gomock.Any(), types.RawToken("testToken")).Return(user, nil)
+
+		handler := NewScopedAuthMiddleware(responser, logger, tokenParser, mocks.NewMockAPIKeyParser(ctrl), types.APIKeyScopeOrdersWrite)(testOkHandler())
+
+		apitest.New().
+			Handler(handler).
+			Post("/").
+			Cookie(AuthCookieName, "testToken").
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+}
@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+)
+
+type CookieSettings struct {
+	Secure bool
+	Domain string
+
+	// MaxAge если не задан, куки живут столько же, сколько токены
+	MaxAge time.Duration
+}
+
+type AuthCookies struct {
+	settings CookieSettings
+}
+
+func (c *AuthCookies) Set(res http.ResponseWriter, tokens models.AuthTokens) {
+	authCookie := c.newCookie(AuthCookieName, "/")
+	authCookie.Value = string(tokens.AccessToken)
+	c.setLifetime(authCookie, tokens.AccessTokenExpiresAt)
+	http.SetCookie(res, authCookie)
+
+	refreshCookie := c.newCookie(RefreshCookieName, "/api/user")
+	refreshCookie.Value = string(tokens.RefreshToken)
+	c.setLifetime(refreshCookie, tokens.RefreshTokenExpiresAt)
+	http.SetCookie(res, refreshCookie)
+}
+
+func (c *AuthCookies) Clear(res http.ResponseWriter) {
+	authCookie := c.newCookie(AuthCookieName, "/")
+	authCookie.MaxAge = -1
+	http.SetCookie(res, authCookie)
+
+	refreshCookie := c.newCookie(RefreshCookieName, "/api/user")
+	refreshCookie.MaxAge = -1
+	http.SetCookie(res, refreshCookie)
+}
+
+func (c *AuthCookies) newCookie(name string, path string) *http.Cookie {
+	return &http.Cookie{
+		Name:   name,
+		Path:   path,
+		Domain: c.settings.Domain,
+
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+		Secure:   c.settings.Secure,
+	}
+}
+
+func (c *AuthCookies) setLifetime(cookie *http.Cookie, expiresAt time.Time) {
+	if c.settings.MaxAge > 0 {
+		cookie.MaxAge = int(c.settings.MaxAge.Seconds())
+		return
+	}
+
+	cookie.Expires = expiresAt
+}
+
+func NewAuthCookies(settings CookieSettings) *AuthCookies {
+	return &AuthCookies{
+		settings: settings,
+	}
+}
@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthCookies(t *testing.T) {
+	tokens := models.AuthTokens{
+		AccessToken:           "access",
+		AccessTokenExpiresAt:  time.Now().Add(time.Minute),
+		RefreshToken:          "refresh",
+		RefreshTokenExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("cookies expire with tokens", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		NewAuthCookies(CookieSettings{}).Set(res, tokens)
+
+		cookies := res.Result().Cookies()
+		require.Len(t, cookies, 2)
+
+		assert.Equal(t, AuthCookieName, cookies[0].Name)
+		assert.Equal(t, "access", cookies[0].Value)
+		assert.Equal(t, 0, cookies[0].MaxAge)
+		assert.False(t, cookies[0].Expires.IsZero())
+		assert.False(t, cookies[0].Secure)
+		assert.True(t, cookies[0].HttpOnly)
+
+		assert.Equal(t, RefreshCookieName, cookies[1].Name)
+		assert.Equal(t, "refresh", cookies[1].Value)
+		assert.Equal(t, "/api/user", cookies[1].Path)
+	})
+
+	t.Run("configured cookies", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		NewAuthCookies(CookieSettings{
+			Secure: true,
+			Domain: "example.com",
+			MaxAge: 24 * time.Hour,
+		}).Set(res, tokens)
+
+		for _, cookie := range res.Result().Cookies() {
+			assert.True(t, cookie.Secure)
+			assert.Equal(t, "example.com", cookie.Domain)
+			assert.Equal(t, 86400, cookie.MaxAge)
+		}
+	})
+
+	t.Run("clear cookies", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		NewAuthCookies(CookieSettings{Domain: "example.com"}).Clear(res)
+
+		cookies := res.Result().Cookies()
+		require.Len(t, cookies, 2)
+		for _, cookie := range cookies {
+			assert.Equal(t, -1, cookie.MaxAge)
+			assert.Equal(t, "example.com", cookie.Domain)
+			assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+		}
+	})
+}
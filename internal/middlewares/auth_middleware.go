@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
 	"github.com/aleksandrpnshkn/gophermart/internal/services"
@@ -11,7 +12,12 @@ import (
 	"go.uber.org/zap"
 )
 
-const AuthCookieName = "auth_token"
+const (
+	AuthCookieName    = "auth_token"
+	RefreshCookieName = "refresh_token"
+
+	bearerPrefix = "bearer "
+)
 
 type TokenParser interface {
 	ParseToken(ctx context.Context, token types.RawToken) (models.User, error)
@@ -26,25 +32,20 @@ func NewAuthMiddleware(
 		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 			ctx := req.Context()
 
-			authCookie, err := req.Cookie(AuthCookieName)
-			if err != nil {
-				if errors.Is(err, http.ErrNoCookie) {
-					res.WriteHeader(http.StatusUnauthorized)
-					return
-				} else {
-					logger.Error("unknown cookie error", zap.Error(err))
-					responser.WriteInternalServerError(ctx, res)
-					return
-				}
+			token, ok := RequestToken(req)
+			if !ok {
+				res.WriteHeader(http.StatusUnauthorized)
+				return
 			}
 
-			var user models.User
-
-			user, err = tokenParser.ParseToken(ctx, types.RawToken(authCookie.Value))
+			user, err := tokenParser.ParseToken(ctx, token)
 			if err != nil {
 				if errors.Is(err, services.ErrInvalidToken) {
 					res.WriteHeader(http.StatusUnauthorized)
 					return
+				} else if errors.Is(err, services.ErrUserLocked) {
+					responser.WriteForbiddenError(ctx, res)
+					return
 				} else {
 					logger.Error("failed to parse token", zap.Error(err))
 					responser.WriteInternalServerError(ctx, res)
@@ -59,14 +60,23 @@ func NewAuthMiddleware(
 	}
 }
 
-func SetAuthCookie(res http.ResponseWriter, token types.RawToken) {
-	authCookie := &http.Cookie{
-		Name:  AuthCookieName,
-		Value: string(token),
+// RequestToken достаёт access токен из заголовка Authorization: Bearer, а если его нет - из куки.
+// Заголовок с другой схемой авторизации не откатывается на куку.
+func RequestToken(req *http.Request) (types.RawToken, bool) {
+	header := req.Header.Get("Authorization")
+	if header != "" {
+		if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+			return types.RawToken(""), false
+		}
 
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-		Secure:   false,
+		token := strings.TrimSpace(header[len(bearerPrefix):])
+		return types.RawToken(token), token != ""
 	}
-	http.SetCookie(res, authCookie)
+
+	authCookie, err := req.Cookie(AuthCookieName)
+	if err != nil || authCookie.Value == "" {
+		return types.RawToken(""), false
+	}
+
+	return types.RawToken(authCookie.Value), true
 }
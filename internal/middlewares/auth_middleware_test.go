@@ -3,6 +3,7 @@ package middlewares
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
@@ -11,6 +12,7 @@ import (
 	"github.com/steinfletcher/apitest"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestAuthMiddleware(t *testing.T) {
@@ -39,8 +41,53 @@ func TestAuthMiddleware(t *testing.T) {
 			End()
 	})
 
+	t.Run("client sent bearer token", func(t *testing.T) {
+		testToken := types.RawToken("testToken")
+
+		tokenParser := mocks.NewMockTokenParser(ctrl)
+		tokenParser.EXPECT().ParseToken(gomock.Any(), testToken).Return(models.User{ID: 123}, nil)
+		handler := NewAuthMiddleware(responser, zap.NewExample(), tokenParser)(testOkHandler())
+
+		apitest.New().
+			Handler(handler).
+			Post("/").
+			Header("Authorization", "Bearer "+string(testToken)).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	})
+
+	t.Run("client sent unsupported authorization scheme", func(t *testing.T) {
+		handler := NewAuthMiddleware(responser, zap.NewExample(), mocks.NewMockTokenParser(ctrl))(testOkHandler())
+
+		apitest.New().
+			Handler(handler).
+			Post("/").
+			Header("Authorization", "Basic dXNlcjpzZWNyZXQ=").
+			Cookie(AuthCookieName, "testToken").
+			Expect(t).
+			Status(http.StatusUnauthorized).
+			End()
+	})
+
+	t.Run("user locked", func(t *testing.T) {
+		testToken := types.RawToken("testToken")
+
+		tokenParser := mocks.NewMockTokenParser(ctrl)
+		tokenParser.EXPECT().ParseToken(gomock.Any(), testToken).Return(models.User{}, services.ErrUserLocked)
+		handler := NewAuthMiddleware(responser, zap.NewExample(), tokenParser)(testOkHandler())
+
+		apitest.New().
+			Handler(handler).
+			Post("/").
+			Cookie(AuthCookieName, string(testToken)).
+			Expect(t).
+			Status(http.StatusForbidden).
+			End()
+	})
+
 	t.Run("client sent invalid token", func(t *testing.T) {
-		auther := services.NewAuther(mocks.NewMockUsersStorage(ctrl), "secretkey")
+		auther := services.NewAuther(mocks.NewMockUsersStorage(ctrl), mocks.NewMockSessionsStorage(ctrl), services.NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
 		handler := NewAuthMiddleware(responser, zap.NewExample(), auther)(testOkHandler())
 
 		apitest.New().
@@ -53,7 +100,7 @@ func TestAuthMiddleware(t *testing.T) {
 	})
 
 	t.Run("client not sent token", func(t *testing.T) {
-		auther := services.NewAuther(mocks.NewMockUsersStorage(ctrl), "secretkey")
+		auther := services.NewAuther(mocks.NewMockUsersStorage(ctrl), mocks.NewMockSessionsStorage(ctrl), services.NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
 		handler := NewAuthMiddleware(responser, zap.NewExample(), auther)(testOkHandler())
 
 		apitest.New().
@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+)
+
+// NewMoneyFormatMiddleware выбирает формат сумм в ответе.
+// Клиент может переопределить формат из конфига через Accept: application/json; money=string
+func NewMoneyFormatMiddleware(defaultFormat responses.MoneyFormat) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			format := defaultFormat
+			if requested, ok := requestedMoneyFormat(req); ok {
+				format = requested
+			}
+
+			res.Header().Add("Vary", "Accept")
+
+			ctx := responses.WithMoneyFormat(req.Context(), format)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		})
+	}
+}
+
+func requestedMoneyFormat(req *http.Request) (responses.MoneyFormat, bool) {
+	for _, accept := range req.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil || (mediaType != "application/json" && mediaType != "*/*") {
+				continue
+			}
+
+			switch responses.MoneyFormat(params["money"]) {
+			case responses.MoneyFormatNumber:
+				return responses.MoneyFormatNumber, true
+			case responses.MoneyFormatString:
+				return responses.MoneyFormatString, true
+			}
+		}
+	}
+
+	return "", false
+}
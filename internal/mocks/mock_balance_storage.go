@@ -12,8 +12,10 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/aleksandrpnshkn/gophermart/internal/models"
+	decimal "github.com/shopspring/decimal"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,6 +43,20 @@ func (m *MockBalanceStorage) EXPECT() *MockBalanceStorageMockRecorder {
 	return m.recorder
 }
 
+// Adjust mocks base method.
+func (m *MockBalanceStorage) Adjust(ctx context.Context, adjustment models.BalanceChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adjust", ctx, adjustment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Adjust indicates an expected call of Adjust.
+func (mr *MockBalanceStorageMockRecorder) Adjust(ctx, adjustment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adjust", reflect.TypeOf((*MockBalanceStorage)(nil).Adjust), ctx, adjustment)
+}
+
 // Close mocks base method.
 func (m *MockBalanceStorage) Close() error {
 	m.ctrl.T.Helper()
@@ -55,6 +71,36 @@ func (mr *MockBalanceStorageMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBalanceStorage)(nil).Close))
 }
 
+// CountHistory mocks base method.
+func (m *MockBalanceStorage) CountHistory(ctx context.Context, user models.User, filter models.BalanceHistoryFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountHistory", ctx, user, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountHistory indicates an expected call of CountHistory.
+func (mr *MockBalanceStorageMockRecorder) CountHistory(ctx, user, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountHistory", reflect.TypeOf((*MockBalanceStorage)(nil).CountHistory), ctx, user, filter)
+}
+
+// GetAdjustments mocks base method.
+func (m *MockBalanceStorage) GetAdjustments(ctx context.Context, user models.User) ([]models.BalanceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdjustments", ctx, user)
+	ret0, _ := ret[0].([]models.BalanceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdjustments indicates an expected call of GetAdjustments.
+func (mr *MockBalanceStorageMockRecorder) GetAdjustments(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdjustments", reflect.TypeOf((*MockBalanceStorage)(nil).GetAdjustments), ctx, user)
+}
+
 // GetBalance mocks base method.
 func (m *MockBalanceStorage) GetBalance(ctx context.Context, user models.User) (models.Balance, error) {
 	m.ctrl.T.Helper()
@@ -70,19 +116,94 @@ func (mr *MockBalanceStorageMockRecorder) GetBalance(ctx, user any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockBalanceStorage)(nil).GetBalance), ctx, user)
 }
 
+// GetBalanceAt mocks base method.
+func (m *MockBalanceStorage) GetBalanceAt(ctx context.Context, user models.User, at time.Time) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAt", ctx, user, at)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAt indicates an expected call of GetBalanceAt.
+func (mr *MockBalanceStorageMockRecorder) GetBalanceAt(ctx, user, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAt", reflect.TypeOf((*MockBalanceStorage)(nil).GetBalanceAt), ctx, user, at)
+}
+
+// GetHistory mocks base method.
+func (m *MockBalanceStorage) GetHistory(ctx context.Context, user models.User, filter models.BalanceHistoryFilter) ([]models.BalanceHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, user, filter)
+	ret0, _ := ret[0].([]models.BalanceHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockBalanceStorageMockRecorder) GetHistory(ctx, user, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockBalanceStorage)(nil).GetHistory), ctx, user, filter)
+}
+
+// GetOrderWithdrawal mocks base method.
+func (m *MockBalanceStorage) GetOrderWithdrawal(ctx context.Context, orderNumber string) (models.BalanceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderWithdrawal", ctx, orderNumber)
+	ret0, _ := ret[0].(models.BalanceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderWithdrawal indicates an expected call of GetOrderWithdrawal.
+func (mr *MockBalanceStorageMockRecorder) GetOrderWithdrawal(ctx, orderNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderWithdrawal", reflect.TypeOf((*MockBalanceStorage)(nil).GetOrderWithdrawal), ctx, orderNumber)
+}
+
+// GetPeriodHistory mocks base method.
+func (m *MockBalanceStorage) GetPeriodHistory(ctx context.Context, user models.User, from, to time.Time) ([]models.BalanceHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeriodHistory", ctx, user, from, to)
+	ret0, _ := ret[0].([]models.BalanceHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeriodHistory indicates an expected call of GetPeriodHistory.
+func (mr *MockBalanceStorageMockRecorder) GetPeriodHistory(ctx, user, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeriodHistory", reflect.TypeOf((*MockBalanceStorage)(nil).GetPeriodHistory), ctx, user, from, to)
+}
+
 // GetWithdrawals mocks base method.
-func (m *MockBalanceStorage) GetWithdrawals(ctx context.Context, user models.User) ([]models.BalanceChange, error) {
+func (m *MockBalanceStorage) GetWithdrawals(ctx context.Context, user models.User, filter models.WithdrawalsFilter) ([]models.BalanceChange, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWithdrawals", ctx, user)
+	ret := m.ctrl.Call(m, "GetWithdrawals", ctx, user, filter)
 	ret0, _ := ret[0].([]models.BalanceChange)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetWithdrawals indicates an expected call of GetWithdrawals.
-func (mr *MockBalanceStorageMockRecorder) GetWithdrawals(ctx, user any) *gomock.Call {
+func (mr *MockBalanceStorageMockRecorder) GetWithdrawals(ctx, user, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawals", reflect.TypeOf((*MockBalanceStorage)(nil).GetWithdrawals), ctx, user, filter)
+}
+
+// GetWithdrawalsSummary mocks base method.
+func (m *MockBalanceStorage) GetWithdrawalsSummary(ctx context.Context, user models.User, filter models.WithdrawalsFilter) (models.WithdrawalsSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithdrawalsSummary", ctx, user, filter)
+	ret0, _ := ret[0].(models.WithdrawalsSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithdrawalsSummary indicates an expected call of GetWithdrawalsSummary.
+func (mr *MockBalanceStorageMockRecorder) GetWithdrawalsSummary(ctx, user, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawals", reflect.TypeOf((*MockBalanceStorage)(nil).GetWithdrawals), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawalsSummary", reflect.TypeOf((*MockBalanceStorage)(nil).GetWithdrawalsSummary), ctx, user, filter)
 }
 
 // Ping mocks base method.
@@ -99,6 +220,21 @@ func (mr *MockBalanceStorageMockRecorder) Ping(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockBalanceStorage)(nil).Ping), ctx)
 }
 
+// Refund mocks base method.
+func (m *MockBalanceStorage) Refund(ctx context.Context, refund models.BalanceChange) (models.BalanceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, refund)
+	ret0, _ := ret[0].(models.BalanceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockBalanceStorageMockRecorder) Refund(ctx, refund any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockBalanceStorage)(nil).Refund), ctx, refund)
+}
+
 // Withdraw mocks base method.
 func (m *MockBalanceStorage) Withdraw(ctx context.Context, withdraw models.BalanceChange) error {
 	m.ctrl.T.Helper()
@@ -56,19 +56,35 @@ func (mr *MockOrdersServiceMockRecorder) Add(ctx, orderNumber, user any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockOrdersService)(nil).Add), ctx, orderNumber, user)
 }
 
+// GetUserOrder mocks base method.
+func (m *MockOrdersService) GetUserOrder(ctx context.Context, user models.User, orderNumber string) (models.Order, []models.OrderStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserOrder", ctx, user, orderNumber)
+	ret0, _ := ret[0].(models.Order)
+	ret1, _ := ret[1].([]models.OrderStatusChange)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserOrder indicates an expected call of GetUserOrder.
+func (mr *MockOrdersServiceMockRecorder) GetUserOrder(ctx, user, orderNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOrder", reflect.TypeOf((*MockOrdersService)(nil).GetUserOrder), ctx, user, orderNumber)
+}
+
 // GetUserOrders mocks base method.
-func (m *MockOrdersService) GetUserOrders(ctx context.Context, user models.User) ([]models.Order, error) {
+func (m *MockOrdersService) GetUserOrders(ctx context.Context, user models.User, filter models.OrdersFilter) (models.OrdersPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserOrders", ctx, user)
-	ret0, _ := ret[0].([]models.Order)
+	ret := m.ctrl.Call(m, "GetUserOrders", ctx, user, filter)
+	ret0, _ := ret[0].(models.OrdersPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserOrders indicates an expected call of GetUserOrders.
-func (mr *MockOrdersServiceMockRecorder) GetUserOrders(ctx, user any) *gomock.Call {
+func (mr *MockOrdersServiceMockRecorder) GetUserOrders(ctx, user, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOrders", reflect.TypeOf((*MockOrdersService)(nil).GetUserOrders), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOrders", reflect.TypeOf((*MockOrdersService)(nil).GetUserOrders), ctx, user, filter)
 }
 
 // HasProcessedStatus mocks base method.
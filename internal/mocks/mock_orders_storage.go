@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	models "github.com/aleksandrpnshkn/gophermart/internal/models"
+	types "github.com/aleksandrpnshkn/gophermart/internal/types"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -55,6 +56,21 @@ func (mr *MockOrdersStorageMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockOrdersStorage)(nil).Close))
 }
 
+// CountUserOrders mocks base method.
+func (m *MockOrdersStorage) CountUserOrders(ctx context.Context, user models.User, filter models.OrdersFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserOrders", ctx, user, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserOrders indicates an expected call of CountUserOrders.
+func (mr *MockOrdersStorageMockRecorder) CountUserOrders(ctx, user, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserOrders", reflect.TypeOf((*MockOrdersStorage)(nil).CountUserOrders), ctx, user, filter)
+}
+
 // Create mocks base method.
 func (m *MockOrdersStorage) Create(ctx context.Context, order models.Order) (models.Order, error) {
 	m.ctrl.T.Helper()
@@ -85,19 +101,49 @@ func (mr *MockOrdersStorageMockRecorder) GetByNumber(ctx, orderNumber any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNumber", reflect.TypeOf((*MockOrdersStorage)(nil).GetByNumber), ctx, orderNumber)
 }
 
+// GetStatusHistory mocks base method.
+func (m *MockOrdersStorage) GetStatusHistory(ctx context.Context, orderNumber string) ([]models.OrderStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", ctx, orderNumber)
+	ret0, _ := ret[0].([]models.OrderStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockOrdersStorageMockRecorder) GetStatusHistory(ctx, orderNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockOrdersStorage)(nil).GetStatusHistory), ctx, orderNumber)
+}
+
+// GetUnfinishedOrders mocks base method.
+func (m *MockOrdersStorage) GetUnfinishedOrders(ctx context.Context, afterNumber string, limit int) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnfinishedOrders", ctx, afterNumber, limit)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnfinishedOrders indicates an expected call of GetUnfinishedOrders.
+func (mr *MockOrdersStorageMockRecorder) GetUnfinishedOrders(ctx, afterNumber, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnfinishedOrders", reflect.TypeOf((*MockOrdersStorage)(nil).GetUnfinishedOrders), ctx, afterNumber, limit)
+}
+
 // GetUserOrders mocks base method.
-func (m *MockOrdersStorage) GetUserOrders(ctx context.Context, user models.User) ([]models.Order, error) {
+func (m *MockOrdersStorage) GetUserOrders(ctx context.Context, user models.User, filter models.OrdersFilter) ([]models.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserOrders", ctx, user)
+	ret := m.ctrl.Call(m, "GetUserOrders", ctx, user, filter)
 	ret0, _ := ret[0].([]models.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserOrders indicates an expected call of GetUserOrders.
-func (mr *MockOrdersStorageMockRecorder) GetUserOrders(ctx, user any) *gomock.Call {
+func (mr *MockOrdersStorageMockRecorder) GetUserOrders(ctx, user, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOrders", reflect.TypeOf((*MockOrdersStorage)(nil).GetUserOrders), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOrders", reflect.TypeOf((*MockOrdersStorage)(nil).GetUserOrders), ctx, user, filter)
 }
 
 // Ping mocks base method.
@@ -115,29 +161,29 @@ func (mr *MockOrdersStorageMockRecorder) Ping(ctx any) *gomock.Call {
 }
 
 // UpdateAccrual mocks base method.
-func (m *MockOrdersStorage) UpdateAccrual(ctx context.Context, order models.Order) error {
+func (m *MockOrdersStorage) UpdateAccrual(ctx context.Context, order models.Order, source types.OrderStatusSource) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccrual", ctx, order)
+	ret := m.ctrl.Call(m, "UpdateAccrual", ctx, order, source)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateAccrual indicates an expected call of UpdateAccrual.
-func (mr *MockOrdersStorageMockRecorder) UpdateAccrual(ctx, order any) *gomock.Call {
+func (mr *MockOrdersStorageMockRecorder) UpdateAccrual(ctx, order, source any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccrual", reflect.TypeOf((*MockOrdersStorage)(nil).UpdateAccrual), ctx, order)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccrual", reflect.TypeOf((*MockOrdersStorage)(nil).UpdateAccrual), ctx, order, source)
 }
 
 // UpdateStatus mocks base method.
-func (m *MockOrdersStorage) UpdateStatus(ctx context.Context, order models.Order) error {
+func (m *MockOrdersStorage) UpdateStatus(ctx context.Context, order models.Order, source types.OrderStatusSource) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatus", ctx, order)
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, order, source)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateStatus indicates an expected call of UpdateStatus.
-func (mr *MockOrdersStorageMockRecorder) UpdateStatus(ctx, order any) *gomock.Call {
+func (mr *MockOrdersStorageMockRecorder) UpdateStatus(ctx, order, source any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockOrdersStorage)(nil).UpdateStatus), ctx, order)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockOrdersStorage)(nil).UpdateStatus), ctx, order, source)
 }
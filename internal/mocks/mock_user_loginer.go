@@ -14,7 +14,6 @@ import (
 	reflect "reflect"
 
 	models "github.com/aleksandrpnshkn/gophermart/internal/models"
-	types "github.com/aleksandrpnshkn/gophermart/internal/types"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -43,11 +42,11 @@ func (m *MockUserLoginer) EXPECT() *MockUserLoginerMockRecorder {
 }
 
 // LoginUser mocks base method.
-func (m *MockUserLoginer) LoginUser(ctx context.Context, login, password string) (models.User, types.RawToken, error) {
+func (m *MockUserLoginer) LoginUser(ctx context.Context, login, password string) (models.User, models.AuthTokens, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "LoginUser", ctx, login, password)
 	ret0, _ := ret[0].(models.User)
-	ret1, _ := ret[1].(types.RawToken)
+	ret1, _ := ret[1].(models.AuthTokens)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
@@ -114,3 +114,46 @@ func (mr *MockUsersStorageMockRecorder) Ping(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockUsersStorage)(nil).Ping), ctx)
 }
+
+// Search mocks base method.
+func (m *MockUsersStorage) Search(ctx context.Context, login string, limit int) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, login, limit)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockUsersStorageMockRecorder) Search(ctx, login, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockUsersStorage)(nil).Search), ctx, login, limit)
+}
+
+// SetLocked mocks base method.
+func (m *MockUsersStorage) SetLocked(ctx context.Context, id int64, locked bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocked", ctx, id, locked)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLocked indicates an expected call of SetLocked.
+func (mr *MockUsersStorageMockRecorder) SetLocked(ctx, id, locked any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocked", reflect.TypeOf((*MockUsersStorage)(nil).SetLocked), ctx, id, locked)
+}
+
+// UpdatePasswordHash mocks base method.
+func (m *MockUsersStorage) UpdatePasswordHash(ctx context.Context, id int64, hash types.PasswordHash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", ctx, id, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
+func (mr *MockUsersStorageMockRecorder) UpdatePasswordHash(ctx, id, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockUsersStorage)(nil).UpdatePasswordHash), ctx, id, hash)
+}
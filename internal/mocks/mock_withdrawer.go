@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	models "github.com/aleksandrpnshkn/gophermart/internal/models"
+	decimal "github.com/shopspring/decimal"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,30 +43,30 @@ func (m *MockWithdrawer) EXPECT() *MockWithdrawerMockRecorder {
 }
 
 // GetWithdrawals mocks base method.
-func (m *MockWithdrawer) GetWithdrawals(ctx context.Context, user models.User) ([]models.BalanceChange, error) {
+func (m *MockWithdrawer) GetWithdrawals(ctx context.Context, user models.User, filter models.WithdrawalsFilter) (models.WithdrawalsPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWithdrawals", ctx, user)
-	ret0, _ := ret[0].([]models.BalanceChange)
+	ret := m.ctrl.Call(m, "GetWithdrawals", ctx, user, filter)
+	ret0, _ := ret[0].(models.WithdrawalsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetWithdrawals indicates an expected call of GetWithdrawals.
-func (mr *MockWithdrawerMockRecorder) GetWithdrawals(ctx, user any) *gomock.Call {
+func (mr *MockWithdrawerMockRecorder) GetWithdrawals(ctx, user, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawals", reflect.TypeOf((*MockWithdrawer)(nil).GetWithdrawals), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawals", reflect.TypeOf((*MockWithdrawer)(nil).GetWithdrawals), ctx, user, filter)
 }
 
 // Withdraw mocks base method.
-func (m *MockWithdrawer) Withdraw(ctx context.Context, orderNumber string, amountRaw float64, user models.User) error {
+func (m *MockWithdrawer) Withdraw(ctx context.Context, orderNumber string, amount decimal.Decimal, user models.User, idempotencyKey string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Withdraw", ctx, orderNumber, amountRaw, user)
+	ret := m.ctrl.Call(m, "Withdraw", ctx, orderNumber, amount, user, idempotencyKey)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Withdraw indicates an expected call of Withdraw.
-func (mr *MockWithdrawerMockRecorder) Withdraw(ctx, orderNumber, amountRaw, user any) *gomock.Call {
+func (mr *MockWithdrawerMockRecorder) Withdraw(ctx, orderNumber, amount, user, idempotencyKey any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockWithdrawer)(nil).Withdraw), ctx, orderNumber, amountRaw, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockWithdrawer)(nil).Withdraw), ctx, orderNumber, amount, user, idempotencyKey)
 }
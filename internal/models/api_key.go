@@ -0,0 +1,24 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+)
+
+// APIKey ключ для интеграции партнёров. Сам ключ не хранится, только его хеш,
+// а Prefix нужен, чтобы пользователь мог отличить ключи в списке.
+type APIKey struct {
+	ID         string
+	UserID     int64
+	Name       string
+	Prefix     string
+	Scopes     []types.APIKeyScope
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+}
+
+func (k APIKey) HasScope(scope types.APIKeyScope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
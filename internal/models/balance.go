@@ -8,3 +8,10 @@ type Balance struct {
 	Current   decimal.Decimal
 	Withdrawn decimal.Decimal
 }
+
+// BalanceMismatch расхождение сохранённого баланса с суммой по журналу
+type BalanceMismatch struct {
+	UserID int64
+	Stored Balance
+	Ledger Balance
+}
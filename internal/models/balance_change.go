@@ -3,12 +3,36 @@ package models
 import (
 	"time"
 
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
 	"github.com/shopspring/decimal"
 )
 
 type BalanceChange struct {
+	ID          string
 	OrderNumber string
 	UserID      int64
 	Amount      decimal.Decimal
 	ProcessedAt time.Time
+	Type        types.BalanceChangeType
+
+	// ключ идемпотентности запроса на списание
+	IdempotencyKey string
+
+	// заполняются только для ручных корректировок
+	Reason     string
+	Reference  string
+	OperatorID int64
+
+	// для возврата - id исходного списания
+	RefundOf string
+
+	// для списания - время возврата, если он был
+	RefundedAt time.Time
+}
+
+// BalanceHistoryEntry запись журнала с балансом пользователя после неё
+type BalanceHistoryEntry struct {
+	BalanceChange
+
+	Balance decimal.Decimal
 }
@@ -0,0 +1,14 @@
+package models
+
+type LoginAttemptKind string
+
+const (
+	LoginAttemptKindLogin LoginAttemptKind = "login"
+	LoginAttemptKindIP    LoginAttemptKind = "ip"
+)
+
+// LoginAttemptKey по чему считаются неудачные попытки входа: по логину или по IP
+type LoginAttemptKey struct {
+	Kind  LoginAttemptKind
+	Value string
+}
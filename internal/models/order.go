@@ -14,3 +14,10 @@ type Order struct {
 	Accrual     decimal.Decimal
 	UploadedAt  time.Time
 }
+
+// OrderStatusChange переход заказа в новый статус
+type OrderStatusChange struct {
+	Status    types.OrderStatus
+	Source    types.OrderStatusSource
+	ChangedAt time.Time
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type OrderJob struct {
+	Order     Order
+	JobName   string
+	Attempts  int
+	RunAt     time.Time
+	LastError string
+	FailedAt  time.Time
+}
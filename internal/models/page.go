@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/shopspring/decimal"
+)
+
+// PageCursor позиция в выдаче: время записи и ключ, который делает порядок однозначным
+type PageCursor struct {
+	At  time.Time
+	Key string
+}
+
+func (c PageCursor) IsZero() bool {
+	return c.At.IsZero() && c.Key == ""
+}
+
+type OrdersFilter struct {
+	Statuses []types.OrderStatus
+
+	// границы по времени загрузки, From включительно, To не включительно
+	From time.Time
+	To   time.Time
+
+	After PageCursor
+
+	// 0 - без ограничения
+	Limit int
+}
+
+type OrdersPage struct {
+	Orders []Order
+	Total  int
+
+	// пустой, если следующей страницы нет
+	Next PageCursor
+}
+
+type WithdrawalsFilter struct {
+	// границы по времени списания, From включительно, To не включительно
+	From time.Time
+	To   time.Time
+
+	After PageCursor
+
+	// 0 - без ограничения
+	Limit int
+}
+
+// WithdrawalsSummary итоги по всем списаниям, попавшим в фильтр, включая возвращённые
+type WithdrawalsSummary struct {
+	Count int
+	Sum   decimal.Decimal
+
+	// сумма списаний, которые потом вернули пользователю
+	Refunded decimal.Decimal
+}
+
+type WithdrawalsPage struct {
+	Withdrawals []BalanceChange
+	Summary     WithdrawalsSummary
+
+	// пустой, если следующей страницы нет
+	Next PageCursor
+}
+
+type BalanceHistoryFilter struct {
+	// границы по времени изменения, From включительно, To не включительно
+	From time.Time
+	To   time.Time
+
+	After PageCursor
+	Limit int
+}
+
+type BalanceHistoryPage struct {
+	Entries []BalanceHistoryEntry
+	Total   int
+
+	// пустой, если следующей страницы нет
+	Next PageCursor
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+)
+
+// Session сессия пользователя, к которой привязаны access и refresh токены
+type Session struct {
+	ID        string
+	UserID    int64
+	CreatedAt time.Time
+
+	// срок действия refresh токена, продлевается при каждой ротации
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
+
+func (s Session) IsRevoked() bool {
+	return !s.RevokedAt.IsZero()
+}
+
+// AuthTokens пара токенов, выдаваемая при входе и при обновлении сессии
+type AuthTokens struct {
+	AccessToken          types.RawToken
+	AccessTokenExpiresAt time.Time
+
+	RefreshToken          types.RawToken
+	RefreshTokenExpiresAt time.Time
+}
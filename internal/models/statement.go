@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Statement выписка по счёту пользователя за период [From, To)
+type Statement struct {
+	Login string
+	From  time.Time
+	To    time.Time
+
+	OpeningBalance decimal.Decimal
+	Credits        decimal.Decimal
+	Debits         decimal.Decimal
+	ClosingBalance decimal.Decimal
+
+	// заказы, загруженные за период
+	OrdersCount int
+
+	Entries []BalanceHistoryEntry
+}
@@ -3,7 +3,9 @@ package models
 import "github.com/aleksandrpnshkn/gophermart/internal/types"
 
 type User struct {
-	ID    int64
-	Login string
-	Hash  types.PasswordHash
+	ID       int64
+	Login    string
+	Hash     types.PasswordHash
+	Role     types.UserRole
+	IsLocked bool
 }
@@ -1,18 +1,58 @@
 package requests
 
+import "github.com/shopspring/decimal"
+
 type (
 	Login struct {
 		Login    string `json:"login" validate:"required,alphanum,min=3,max=30"`
-		Password string `json:"password" validate:"required,alphanum,min=6,max=50"`
+		Password string `json:"password" validate:"required,max=72"`
+
+		// ReturnTokens вернуть токены в теле ответа, а не только в куках
+		ReturnTokens bool `json:"return_tokens"`
 	}
 
 	Register struct {
 		Login    string `json:"login" validate:"required,alphanum,min=3,max=30"`
-		Password string `json:"password" validate:"required,alphanum,min=6,max=50"`
+		Password string `json:"password" validate:"required,password"`
+
+		ReturnTokens bool `json:"return_tokens"`
+	}
+
+	ChangePassword struct {
+		CurrentPassword string `json:"current_password" validate:"required,max=72"`
+		NewPassword     string `json:"new_password" validate:"required,password,nefield=CurrentPassword"`
+	}
+
+	CreateAPIKey struct {
+		Name   string   `json:"name" validate:"required,max=100"`
+		Scopes []string `json:"scopes" validate:"required,min=1,unique,dive,oneof=orders:write balance:read"`
+	}
+
+	RefreshTokens struct {
+		RefreshToken string `json:"refresh_token"`
 	}
 
 	Withdraw struct {
-		OrderNumber string  `json:"order" validate:"required,numeric,min=3,max=100,luhn"`
-		Amount      float64 `json:"sum" validate:"required,number,min=1"`
+		OrderNumber string `json:"order" validate:"required,numeric,min=3,max=100,luhn"`
+
+		// сумма принимается и числом, и строкой, без округления через float
+		Amount decimal.Decimal `json:"sum" validate:"required,decimal_min=1"`
+	}
+
+	BalanceAdjustment struct {
+		Amount    decimal.Decimal `json:"amount" validate:"required"`
+		Reason    string          `json:"reason" validate:"required,max=500"`
+		Reference string          `json:"reference" validate:"max=100"`
+	}
+
+	// AccrualWebhook статус заказа от системы расчёта баллов, формат как у GET /api/orders/{number}
+	AccrualWebhook struct {
+		OrderNumber string          `json:"order" validate:"required,numeric,max=100"`
+		Status      string          `json:"status" validate:"required,oneof=REGISTERED INVALID PROCESSING PROCESSED"`
+		Accrual     decimal.Decimal `json:"accrual"`
+	}
+
+	WithdrawalRefund struct {
+		Reason string `json:"reason" validate:"required,max=500"`
 	}
 )
@@ -0,0 +1,59 @@
+package responses
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+type MoneyFormat string
+
+const (
+	// сумма числом без потери точности: {"sum": 0.3}
+	MoneyFormatNumber MoneyFormat = "number"
+
+	// сумма строкой для клиентов, которые парсят числа во float: {"sum": "0.3"}
+	MoneyFormatString MoneyFormat = "string"
+)
+
+type moneyFormatKey struct{}
+
+// Money сумма в баллах, кодируется в JSON точно, без перевода во float
+type Money struct {
+	amount decimal.Decimal
+	format MoneyFormat
+}
+
+func NewMoney(amount decimal.Decimal, format MoneyFormat) Money {
+	return Money{
+		amount: amount,
+		format: format,
+	}
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	if m.format == MoneyFormatString {
+		return []byte(strconv.Quote(m.amount.String())), nil
+	}
+
+	return []byte(m.amount.String()), nil
+}
+
+// String сумма без кавычек, например для заголовков ответа
+func (m Money) String() string {
+	return m.amount.String()
+}
+
+func WithMoneyFormat(ctx context.Context, format MoneyFormat) context.Context {
+	return context.WithValue(ctx, moneyFormatKey{}, format)
+}
+
+// MoneyFormatFromContext формат, выбранный клиентом, по умолчанию число
+func MoneyFormatFromContext(ctx context.Context) MoneyFormat {
+	format, ok := ctx.Value(moneyFormatKey{}).(MoneyFormat)
+	if !ok {
+		return MoneyFormatNumber
+	}
+	return format
+}
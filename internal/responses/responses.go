@@ -28,22 +28,114 @@ type (
 		Result bool `json:"result"`
 	}
 
+	Health struct {
+		Status  string `json:"status"`
+		Accrual string `json:"accrual"`
+	}
+
+	AuthTokens struct {
+		AccessToken           string `json:"access_token"`
+		TokenType             string `json:"token_type"`
+		ExpiresIn             int64  `json:"expires_in"`
+		RefreshToken          string `json:"refresh_token"`
+		RefreshTokenExpiresIn int64  `json:"refresh_token_expires_in"`
+	}
+
+	APIKey struct {
+		ID         string   `json:"id"`
+		Name       string   `json:"name"`
+		Prefix     string   `json:"prefix"`
+		Scopes     []string `json:"scopes"`
+		CreatedAt  string   `json:"created_at"`
+		LastUsedAt string   `json:"last_used_at,omitempty"`
+	}
+
+	CreatedAPIKey struct {
+		APIKey
+		Key string `json:"key"`
+	}
+
+	JWKS struct {
+		Keys []JWK `json:"keys"`
+	}
+
+	JWK struct {
+		KeyType   string `json:"kty"`
+		KeyID     string `json:"kid"`
+		Use       string `json:"use"`
+		Algorithm string `json:"alg"`
+
+		// RSA
+		Modulus  string `json:"n,omitempty"`
+		Exponent string `json:"e,omitempty"`
+
+		// Ed25519
+		Curve string `json:"crv,omitempty"`
+		X     string `json:"x,omitempty"`
+	}
+
 	Order struct {
-		OrderNumber string  `json:"number"`
-		Status      string  `json:"status"`
-		Accrual     float64 `json:"accrual,omitempty"`
-		UploadedAt  string  `json:"uploaded_at"`
+		OrderNumber string `json:"number"`
+		Status      string `json:"status"`
+		Accrual     *Money `json:"accrual,omitempty"`
+		UploadedAt  string `json:"uploaded_at"`
+	}
+
+	OrderDetails struct {
+		Order
+		History []OrderStatusChange `json:"history"`
+	}
+
+	OrderStatusChange struct {
+		Status    string `json:"status"`
+		Source    string `json:"source"`
+		ChangedAt string `json:"changed_at"`
 	}
 
 	Balance struct {
-		Current   float64 `json:"current"`
-		Withdrawn float64 `json:"withdrawn"`
+		Current   Money `json:"current"`
+		Withdrawn Money `json:"withdrawn"`
 	}
 
 	Withdraw struct {
-		OrderNumber string  `json:"order"`
-		Sum         float64 `json:"sum"`
-		ProcessedAt string  `json:"processed_at"`
+		OrderNumber string `json:"order"`
+		Sum         Money  `json:"sum"`
+		Status      string `json:"status"`
+		ProcessedAt string `json:"processed_at"`
+		RefundedAt  string `json:"refunded_at,omitempty"`
+	}
+
+	BalanceHistoryEntry struct {
+		Type        string `json:"type"`
+		OrderNumber string `json:"order,omitempty"`
+		Amount      Money  `json:"amount"`
+		Balance     Money  `json:"balance"`
+		ProcessedAt string `json:"processed_at"`
+	}
+
+	BalanceAdjustment struct {
+		Amount      Money  `json:"amount"`
+		Reason      string `json:"reason"`
+		Reference   string `json:"reference"`
+		OperatorID  int64  `json:"operator_id"`
+		ProcessedAt string `json:"processed_at"`
+	}
+
+	User struct {
+		ID       int64  `json:"id"`
+		Login    string `json:"login"`
+		Role     string `json:"role"`
+		IsLocked bool   `json:"is_locked"`
+	}
+
+	DeadLetter struct {
+		OrderNumber string `json:"order"`
+		OrderStatus string `json:"order_status"`
+		UserID      int64  `json:"user_id"`
+		JobName     string `json:"job"`
+		Attempts    int    `json:"attempts"`
+		LastError   string `json:"last_error"`
+		FailedAt    string `json:"failed_at"`
 	}
 )
 
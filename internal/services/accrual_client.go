@@ -0,0 +1,42 @@
+package services
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+type AccrualClientSettings struct {
+	ConnectTimeout time.Duration
+	ReadTimeout    time.Duration
+
+	// MaxConns соединений к accrual, обычно по одному на воркер очереди
+	MaxConns int
+}
+
+// NewAccrualHTTPClient клиент с таймаутами на каждый этап запроса.
+// Общий таймаут запроса дополнительно ограничен контекстом джобы.
+func NewAccrualHTTPClient(settings AccrualClientSettings) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   settings.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   settings.ConnectTimeout,
+		ResponseHeaderTimeout: settings.ReadTimeout,
+		ExpectContinueTimeout: time.Second,
+		MaxIdleConns:          settings.MaxConns,
+		MaxIdleConnsPerHost:   settings.MaxConns,
+		MaxConnsPerHost:       settings.MaxConns,
+		IdleConnTimeout:       90 * time.Second,
+		ForceAttemptHTTP2:     true,
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   settings.ConnectTimeout + settings.ReadTimeout,
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AccrualLimiter общий на весь процесс ограничитель запросов в систему расчёта баллов.
+// Держит постоянный темп запросов и останавливает всех воркеров, если accrual попросил подождать.
+type AccrualLimiter struct {
+	mu sync.Mutex
+
+	interval    time.Duration
+	nextSlot    time.Time
+	pausedUntil time.Time
+}
+
+// Wait блокирует до момента, когда можно отправить очередной запрос
+func (l *AccrualLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// PauseFor приостанавливает запросы всех воркеров, уже назначенная пауза не сокращается
+func (l *AccrualLimiter) PauseFor(delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	pausedUntil := time.Now().Add(delay)
+	if pausedUntil.After(l.pausedUntil) {
+		l.pausedUntil = pausedUntil
+	}
+}
+
+// PausedFor возвращает, сколько ещё продлится пауза
+func (l *AccrualLimiter) PausedFor() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return time.Until(l.pausedUntil)
+}
+
+// reserve занимает слот для запроса или возвращает время, через которое стоит попробовать снова
+func (l *AccrualLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	if l.pausedUntil.After(now) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	if l.nextSlot.After(now) {
+		return l.nextSlot.Sub(now)
+	}
+
+	l.nextSlot = now.Add(l.interval)
+	return 0
+}
+
+// NewAccrualLimiter создаёт ограничитель на requestsPerSecond запросов в секунду, 0 - без ограничения темпа
+func NewAccrualLimiter(requestsPerSecond float64) *AccrualLimiter {
+	interval := time.Duration(0)
+	if requestsPerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+
+	return &AccrualLimiter{
+		interval: interval,
+	}
+}
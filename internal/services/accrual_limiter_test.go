@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccrualLimiter(t *testing.T) {
+	t.Run("unlimited rate", func(t *testing.T) {
+		limiter := NewAccrualLimiter(0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, limiter.Wait(ctx))
+		}
+	})
+
+	t.Run("steady rate", func(t *testing.T) {
+		limiter := NewAccrualLimiter(100)
+
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			assert.NoError(t, limiter.Wait(context.Background()))
+		}
+
+		// первый запрос проходит сразу, остальные через 10ms
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("paused for all callers", func(t *testing.T) {
+		limiter := NewAccrualLimiter(0)
+		limiter.PauseFor(time.Second)
+
+		assert.Greater(t, limiter.PausedFor(), 900*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("pause is not shortened", func(t *testing.T) {
+		limiter := NewAccrualLimiter(0)
+		limiter.PauseFor(time.Second)
+		limiter.PauseFor(time.Millisecond)
+
+		assert.Greater(t, limiter.PausedFor(), 900*time.Millisecond)
+	})
+
+	t.Run("pause expired", func(t *testing.T) {
+		limiter := NewAccrualLimiter(0)
+		limiter.PauseFor(10 * time.Millisecond)
+
+		start := time.Now()
+		assert.NoError(t, limiter.Wait(context.Background()))
+		assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	})
+}
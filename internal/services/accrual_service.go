@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
@@ -21,12 +23,13 @@ var (
 	ErrAccrualOrderNotFound      = errors.New("order not found")
 	ErrAccrualFailedToGet        = errors.New("failed to get accrual")
 	ErrAccrualUnexpectedError    = errors.New("failed to get accrual with unexpected error")
+	ErrAccrualNegativeAmount     = errors.New("accrual is negative")
 )
 
 type AccrualResponse struct {
-	Order   string  `json:"order"`
-	Status  string  `json:"status"`
-	Accrual float64 `json:"accrual"`
+	Order   string          `json:"order"`
+	Status  string          `json:"status"`
+	Accrual decimal.Decimal `json:"accrual"`
 }
 
 const (
@@ -53,6 +56,8 @@ func (e *ErrAccrualFailedToGetWithRetry) Error() string {
 
 type AccrualService struct {
 	client  *http.Client
+	limiter *AccrualLimiter
+	breaker *CircuitBreaker
 	baseURL string
 	logger  *zap.Logger
 }
@@ -72,12 +77,55 @@ func (a *AccrualService) GetAccrual(
 		return zero, err
 	}
 
-	res, err := a.client.Get(accrualURL)
+	// не держим воркер внутри джобы всю паузу, а возвращаем задачу в очередь
+	pausedFor := a.limiter.PausedFor()
+	if pausedFor > 0 {
+		return zero, &ErrAccrualFailedToGetWithRetry{
+			RetryAfter: int(math.Ceil(pausedFor.Seconds())),
+		}
+	}
+
+	err = a.limiter.Wait(ctx)
 	if err != nil {
 		return zero, err
 	}
+
+	allowed, openFor := a.breaker.Allow()
+	if !allowed {
+		return zero, &ErrAccrualFailedToGetWithRetry{
+			RetryAfter: int(math.Ceil(openFor.Seconds())),
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, accrualURL, nil)
+	if err != nil {
+		a.breaker.Release()
+		return zero, err
+	}
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		// приложение останавливается, о состоянии accrual это ничего не говорит
+		if errors.Is(err, context.Canceled) {
+			a.breaker.Release()
+			return zero, err
+		}
+
+		a.breaker.Failure()
+		a.logger.Error("failed to send accrual request",
+			zap.String("order_number", orderNumber),
+			zap.Error(err),
+		)
+		return zero, fmt.Errorf("%w: %w", ErrAccrualFailedToGet, err)
+	}
 	defer res.Body.Close()
 
+	if res.StatusCode >= http.StatusInternalServerError {
+		a.breaker.Failure()
+	} else {
+		a.breaker.Success()
+	}
+
 	a.logger.Debug("accrual responsed",
 		zap.String("order_number", orderNumber),
 		zap.Int("status_code", res.StatusCode),
@@ -99,7 +147,9 @@ func (a *AccrualService) GetAccrual(
 
 		if rawRetryAfter != "" {
 			retryAfter, err := strconv.Atoi(rawRetryAfter)
-			if err != nil && retryAfter > 0 {
+			if err == nil && retryAfter > 0 {
+				a.limiter.PauseFor(time.Duration(retryAfter) * time.Second)
+
 				return zero, &ErrAccrualFailedToGetWithRetry{
 					RetryAfter: retryAfter,
 				}
@@ -140,7 +190,15 @@ func (a *AccrualService) GetAccrual(
 	case statusInvalid:
 		return zero, ErrAccrualInvalidStatus
 	case statusProcessed:
-		return decimal.NewFromFloat(accrualOrder.Accrual), nil
+		// то же правило, что и для вебхука: отрицательное начисление списало бы баллы
+		if accrualOrder.Accrual.IsNegative() {
+			a.logger.Error("negative accrual from accrual",
+				zap.String("order_number", orderNumber),
+				zap.String("accrual", accrualOrder.Accrual.String()),
+			)
+			return zero, ErrAccrualNegativeAmount
+		}
+		return accrualOrder.Accrual, nil
 	case statusRegistered, statusProcessing:
 		return zero, ErrAccrualNotProcessedStatus
 	default:
@@ -154,11 +212,15 @@ func (a *AccrualService) GetAccrual(
 
 func NewAccrualService(
 	client *http.Client,
+	limiter *AccrualLimiter,
+	breaker *CircuitBreaker,
 	logger *zap.Logger,
 	baseURL string,
 ) *AccrualService {
 	return &AccrualService{
 		client:  client,
+		limiter: limiter,
+		breaker: breaker,
 		baseURL: baseURL,
 		logger:  logger,
 	}
@@ -5,7 +5,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/aleksandrpnshkn/gophermart/internal/accrualfake"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -46,6 +49,24 @@ func TestAccrualService(t *testing.T) {
             }`,
 			expectedAccrual: "729.98",
 		},
+		{
+			testName: "accrual beyond float precision",
+			accrualRawResponse: `{
+                "order": "<number>",
+                "status": "PROCESSED",
+                "accrual": 9007199254740993.01
+            }`,
+			expectedAccrual: "9007199254740993.01",
+		},
+		{
+			testName: "accrual as string",
+			accrualRawResponse: `{
+                "order": "<number>",
+                "status": "PROCESSED",
+                "accrual": "0.3"
+            }`,
+			expectedAccrual: "0.3",
+		},
 	}
 
 	for _, test := range tests {
@@ -59,7 +80,7 @@ func TestAccrualService(t *testing.T) {
 
 			client := srv.Client()
 
-			accrualer := NewAccrualService(client, logger, srv.URL)
+			accrualer := NewAccrualService(client, NewAccrualLimiter(0), NewCircuitBreaker("accrual", 5, time.Minute, logger), logger, srv.URL)
 
 			accrual, err := accrualer.GetAccrual(context.Background(), "123")
 
@@ -68,3 +89,168 @@ func TestAccrualService(t *testing.T) {
 		})
 	}
 }
+
+func TestAccrualServiceRetryAfter(t *testing.T) {
+	logger := zap.NewExample()
+
+	requestsCount := 0
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			requestsCount++
+			res.Header().Set("Retry-After", "60")
+			res.WriteHeader(http.StatusTooManyRequests)
+		}),
+	)
+	defer srv.Close()
+
+	limiter := NewAccrualLimiter(0)
+	accrualer := NewAccrualService(srv.Client(), limiter, NewCircuitBreaker("accrual", 5, time.Minute, logger), logger, srv.URL)
+
+	_, err := accrualer.GetAccrual(context.Background(), "123")
+
+	var retryErr *ErrAccrualFailedToGetWithRetry
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, 60, retryErr.RetryAfter)
+
+	// остальные воркеры не должны ходить в accrual до окончания паузы
+	otherAccrualer := NewAccrualService(srv.Client(), limiter, NewCircuitBreaker("accrual", 5, time.Minute, logger), logger, srv.URL)
+	_, err = otherAccrualer.GetAccrual(context.Background(), "456")
+
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, 60, retryErr.RetryAfter)
+	assert.Equal(t, 1, requestsCount)
+}
+
+func TestAccrualServiceCircuitBreaker(t *testing.T) {
+	logger := zap.NewExample()
+
+	requestsCount := 0
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			requestsCount++
+			res.WriteHeader(http.StatusBadGateway)
+		}),
+	)
+	defer srv.Close()
+
+	breaker := NewCircuitBreaker("accrual", 2, time.Minute, logger)
+	accrualer := NewAccrualService(srv.Client(), NewAccrualLimiter(0), breaker, logger, srv.URL)
+
+	for i := 0; i < 2; i++ {
+		_, err := accrualer.GetAccrual(context.Background(), "123")
+		require.ErrorIs(t, err, ErrAccrualFailedToGet)
+	}
+	assert.Equal(t, CircuitStateOpen, breaker.State())
+
+	// пока цепь открыта, воркер ставится на паузу без запроса в accrual
+	_, err := accrualer.GetAccrual(context.Background(), "123")
+
+	var retryErr *ErrAccrualFailedToGetWithRetry
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, 60, retryErr.RetryAfter)
+	assert.Equal(t, 2, requestsCount)
+}
+
+func TestAccrualServiceJobContext(t *testing.T) {
+	logger := zap.NewExample()
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			select {
+			case <-req.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}),
+	)
+	defer srv.Close()
+
+	breaker := NewCircuitBreaker("accrual", 5, time.Minute, logger)
+	accrualer := NewAccrualService(srv.Client(), NewAccrualLimiter(0), breaker, logger, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := accrualer.GetAccrual(ctx, "123")
+
+	// таймаут джобы прерывает запрос и считается ошибкой accrual, которую стоит повторить
+	assert.ErrorIs(t, err, ErrAccrualFailedToGet)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestAccrualServiceWithFake(t *testing.T) {
+	logger := zap.NewExample()
+
+	accrual := accrualfake.NewTestServer(accrualfake.Config{
+		Default: []accrualfake.Step{
+			{Status: accrualfake.StatusRegistered},
+			{Status: accrualfake.StatusProcessing},
+			{Status: accrualfake.StatusProcessed, Accrual: decimal.RequireFromString("729.98")},
+		},
+		Orders: map[string][]accrualfake.Step{
+			"111": {{Status: accrualfake.StatusInvalid}},
+			"222": {},
+			"333": {{Code: http.StatusTooManyRequests, RetryAfter: 30}},
+			"444": {{Code: http.StatusInternalServerError}},
+			"555": {{Status: accrualfake.StatusProcessed, Accrual: decimal.NewFromInt(1), Latency: accrualfake.Duration(time.Second)}},
+			"666": {{Status: accrualfake.StatusProcessed, Accrual: decimal.NewFromInt(-5)}},
+		},
+	})
+	defer accrual.Close()
+
+	newAccrualer := func() *AccrualService {
+		return NewAccrualService(accrual.Client(), NewAccrualLimiter(0), NewCircuitBreaker("accrual", 5, time.Minute, logger), logger, accrual.URL)
+	}
+
+	t.Run("order goes through statuses", func(t *testing.T) {
+		accrualer := newAccrualer()
+
+		_, err := accrualer.GetAccrual(context.Background(), "12345678903")
+		assert.ErrorIs(t, err, ErrAccrualNotProcessedStatus)
+
+		_, err = accrualer.GetAccrual(context.Background(), "12345678903")
+		assert.ErrorIs(t, err, ErrAccrualNotProcessedStatus)
+
+		amount, err := accrualer.GetAccrual(context.Background(), "12345678903")
+		require.NoError(t, err)
+		assert.Equal(t, "729.98", amount.String())
+
+		assert.Equal(t, 3, accrual.Requests("12345678903"))
+	})
+
+	t.Run("invalid order", func(t *testing.T) {
+		_, err := newAccrualer().GetAccrual(context.Background(), "111")
+		assert.ErrorIs(t, err, ErrAccrualInvalidStatus)
+	})
+
+	t.Run("unknown order", func(t *testing.T) {
+		_, err := newAccrualer().GetAccrual(context.Background(), "222")
+		assert.ErrorIs(t, err, ErrAccrualOrderNotFound)
+	})
+
+	t.Run("too many requests", func(t *testing.T) {
+		_, err := newAccrualer().GetAccrual(context.Background(), "333")
+
+		var retryErr *ErrAccrualFailedToGetWithRetry
+		require.ErrorAs(t, err, &retryErr)
+		assert.Equal(t, 30, retryErr.RetryAfter)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		_, err := newAccrualer().GetAccrual(context.Background(), "444")
+		assert.ErrorIs(t, err, ErrAccrualFailedToGet)
+	})
+
+	t.Run("negative accrual", func(t *testing.T) {
+		_, err := newAccrualer().GetAccrual(context.Background(), "666")
+		assert.ErrorIs(t, err, ErrAccrualNegativeAmount)
+	})
+
+	t.Run("slow response", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := newAccrualer().GetAccrual(ctx, "555")
+		assert.ErrorIs(t, err, ErrAccrualFailedToGet)
+	})
+}
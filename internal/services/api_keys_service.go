@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/apikeys"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/users"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// по префиксу ключ легко узнать в логах и сканерах секретов
+	apiKeyPrefix = "gm_"
+
+	// сколько первых символов ключа показывать пользователю
+	apiKeyVisiblePrefixLength = len(apiKeyPrefix) + 8
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+type APIKeysService struct {
+	storage      apikeys.Storage
+	usersStorage users.Storage
+
+	logger *zap.Logger
+}
+
+// Create возвращает ключ целиком, больше его получить нельзя
+func (s *APIKeysService) Create(
+	ctx context.Context,
+	user models.User,
+	name string,
+	scopes []types.APIKeyScope,
+) (models.APIKey, types.RawToken, error) {
+	secret, err := randomToken(32)
+	if err != nil {
+		return models.APIKey{}, types.RawToken(""), err
+	}
+	rawKey := types.RawToken(apiKeyPrefix + secret)
+
+	key, err := s.storage.Create(ctx, models.APIKey{
+		UserID: user.ID,
+		Name:   name,
+		Prefix: string(rawKey[:apiKeyVisiblePrefixLength]),
+		Scopes: scopes,
+	}, hashToken(rawKey))
+	if err != nil {
+		return models.APIKey{}, types.RawToken(""), err
+	}
+
+	s.logger.Info("api key created",
+		zap.Int64("user_id", user.ID),
+		zap.String("api_key_id", key.ID),
+	)
+
+	return key, rawKey, nil
+}
+
+func (s *APIKeysService) GetUserKeys(ctx context.Context, user models.User) ([]models.APIKey, error) {
+	return s.storage.GetUserKeys(ctx, user.ID)
+}
+
+func (s *APIKeysService) Revoke(ctx context.Context, user models.User, id string) error {
+	err := s.storage.Revoke(ctx, user.ID, id)
+	if err != nil {
+		if errors.Is(err, apikeys.ErrAPIKeyNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	s.logger.Info("api key revoked",
+		zap.Int64("user_id", user.ID),
+		zap.String("api_key_id", id),
+	)
+
+	return nil
+}
+
+func (s *APIKeysService) ParseAPIKey(ctx context.Context, rawKey types.RawToken) (models.User, models.APIKey, error) {
+	if len(rawKey) <= apiKeyVisiblePrefixLength || string(rawKey[:len(apiKeyPrefix)]) != apiKeyPrefix {
+		return models.User{}, models.APIKey{}, ErrInvalidAPIKey
+	}
+
+	key, err := s.storage.Use(ctx, hashToken(rawKey))
+	if err != nil {
+		if errors.Is(err, apikeys.ErrAPIKeyNotFound) {
+			return models.User{}, models.APIKey{}, ErrInvalidAPIKey
+		}
+		return models.User{}, models.APIKey{}, err
+	}
+
+	user, err := s.usersStorage.GetByID(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, users.ErrUserNotFound) {
+			return models.User{}, models.APIKey{}, ErrInvalidAPIKey
+		}
+		return models.User{}, models.APIKey{}, err
+	}
+
+	if user.IsLocked {
+		return models.User{}, models.APIKey{}, ErrUserLocked
+	}
+
+	return user, key, nil
+}
+
+func NewAPIKeysService(
+	storage apikeys.Storage,
+	usersStorage users.Storage,
+	logger *zap.Logger,
+) *APIKeysService {
+	return &APIKeysService{
+		storage:      storage,
+		usersStorage: usersStorage,
+		logger:       logger,
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/apikeys"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestAPIKeysService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	logger := zap.NewExample()
+
+	user := models.User{
+		ID:    1,
+		Login: "admin",
+	}
+
+	t.Run("create", func(t *testing.T) {
+		storage := mocks.NewMockAPIKeysStorage(ctrl)
+
+		var storedHash []byte
+		storage.EXPECT().
+			Create(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key models.APIKey, keyHash []byte) (models.APIKey, error) {
+				storedHash = keyHash
+				key.ID = "key-id"
+				return key, nil
+			})
+
+		service := NewAPIKeysService(storage, mocks.NewMockUsersStorage(ctrl), logger)
+
+		key, rawKey, err := service.Create(context.Background(), user, "shop", []types.APIKeyScope{types.APIKeyScopeOrdersWrite})
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(string(rawKey), "gm_"))
+		assert.True(t, strings.HasPrefix(string(rawKey), key.Prefix))
+		assert.Len(t, key.Prefix, 11)
+		assert.Equal(t, hashToken(rawKey), storedHash)
+		assert.NotContains(t, string(storedHash), string(rawKey))
+	})
+
+	t.Run("malformed key", func(t *testing.T) {
+		service := NewAPIKeysService(mocks.NewMockAPIKeysStorage(ctrl), mocks.NewMockUsersStorage(ctrl), logger)
+
+		_, _, err := service.ParseAPIKey(context.Background(), types.RawToken("not-a-key"))
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("revoked key", func(t *testing.T) {
+		storage := mocks.NewMockAPIKeysStorage(ctrl)
+		storage.EXPECT().Use(gomock.Any(), gomock.Any()).Return(models.APIKey{}, apikeys.ErrAPIKeyNotFound)
+
+		service := NewAPIKeysService(storage, mocks.NewMockUsersStorage(ctrl), logger)
+
+		_, _, err := service.ParseAPIKey(context.Background(), types.RawToken("gm_0123456789abcdef"))
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("locked user", func(t *testing.T) {
+		rawKey := types.RawToken("gm_0123456789abcdef")
+
+		storage := mocks.NewMockAPIKeysStorage(ctrl)
+		storage.EXPECT().Use(gomock.Any(), hashToken(rawKey)).Return(models.APIKey{UserID: user.ID}, nil)
+
+		lockedUser := user
+		lockedUser.IsLocked = true
+		usersStorage := mocks.NewMockUsersStorage(ctrl)
+		usersStorage.EXPECT().GetByID(gomock.Any(), user.ID).Return(lockedUser, nil)
+
+		service := NewAPIKeysService(storage, usersStorage, logger)
+
+		_, _, err := service.ParseAPIKey(context.Background(), rawKey)
+		assert.ErrorIs(t, err, ErrUserLocked)
+	})
+}
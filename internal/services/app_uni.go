@@ -27,6 +27,22 @@ func (u *AppUni) RegisterValidationTranslations(validate *validator.Validate) {
 	en_translations.RegisterDefaultTranslations(validate, defaultTrans)
 }
 
+// RegisterValidationTranslation текст ошибки для собственного правила валидации, {0} - имя поля
+func (u *AppUni) RegisterValidationTranslation(validate *validator.Validate, tag string, text string) {
+	defaultTrans, _ := u.uni.GetTranslator(defaultTrans)
+	validate.RegisterTranslation(
+		tag,
+		defaultTrans,
+		func(trans ut.Translator) error {
+			return trans.Add(tag, text, true)
+		},
+		func(trans ut.Translator, fe validator.FieldError) string {
+			message, _ := trans.T(tag, fe.Field(), fe.Param())
+			return message
+		},
+	)
+}
+
 func NewAppUni() *AppUni {
 	en := en.New()
 	uni := ut.New(en, en)
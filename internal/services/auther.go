@@ -2,24 +2,38 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"time"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/sessions"
 	"github.com/aleksandrpnshkn/gophermart/internal/storage/users"
 	"github.com/aleksandrpnshkn/gophermart/internal/types"
 	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type Claims struct {
 	jwt.RegisteredClaims
-	UserID int64
+	UserID    int64
+	SessionID string
 }
 
 type JwtAuther struct {
-	usersStorage users.Storage
+	usersStorage    users.Storage
+	sessionsStorage sessions.Storage
 
-	secretKey string
+	keys            *JwtKeys
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+
+	passwordCost int
+
+	logger *zap.Logger
 }
 
 type ctxKey string
@@ -28,20 +42,40 @@ var (
 	ErrInvalidToken       = errors.New("invalid token")
 	ErrBadCredentials     = errors.New("bad credentials")
 	ErrLoginAlreadyExists = errors.New("login already exists")
+	ErrUserLocked         = errors.New("user is locked")
 )
 
 const ctxUserID ctxKey = "user_id"
 
 func (a *JwtAuther) ParseToken(ctx context.Context, tokenString types.RawToken) (models.User, error) {
-	token, err := jwt.ParseWithClaims(string(tokenString), &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		return []byte(a.secretKey), nil
-	})
+	token, err := jwt.ParseWithClaims(
+		string(tokenString),
+		&Claims{},
+		a.keys.KeyFunc,
+		jwt.WithValidMethods(a.keys.ValidMethods()),
+	)
 	if err != nil {
 		return models.User{}, ErrInvalidToken
 	}
 
 	claims := token.Claims.(*Claims)
 
+	// Токены без срока действия выпускались до появления сессий
+	if claims.ExpiresAt == nil || claims.SessionID == "" {
+		return models.User{}, ErrInvalidToken
+	}
+
+	session, err := a.sessionsStorage.GetByID(ctx, claims.SessionID)
+	if err != nil {
+		if errors.Is(err, sessions.ErrSessionNotFound) {
+			return models.User{}, ErrInvalidToken
+		}
+		return models.User{}, err
+	}
+	if session.IsRevoked() || session.UserID != claims.UserID {
+		return models.User{}, ErrInvalidToken
+	}
+
 	user, err := a.usersStorage.GetByID(ctx, claims.UserID)
 	if err != nil {
 		if errors.Is(err, users.ErrUserNotFound) {
@@ -51,6 +85,10 @@ func (a *JwtAuther) ParseToken(ctx context.Context, tokenString types.RawToken)
 		}
 	}
 
+	if user.IsLocked {
+		return models.User{}, ErrUserLocked
+	}
+
 	return user, nil
 }
 
@@ -58,57 +96,158 @@ func (a *JwtAuther) RegisterUser(
 	ctx context.Context,
 	login string,
 	password string,
-) (models.User, types.RawToken, error) {
-	token := types.RawToken("")
-	hashBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+) (models.User, models.AuthTokens, error) {
+	var tokens models.AuthTokens
+	hash, err := a.hashPassword(password)
 	if err != nil {
-		return models.User{}, token, err
+		return models.User{}, tokens, err
 	}
-	hash := types.PasswordHash(hashBytes)
 
 	user, err := a.usersStorage.Create(ctx, login, hash)
 	if err != nil {
 		if errors.Is(err, users.ErrUserAlreadyExists) {
-			return models.User{}, token, ErrLoginAlreadyExists
+			return models.User{}, tokens, ErrLoginAlreadyExists
 		}
 
-		return models.User{}, token, err
+		return models.User{}, tokens, err
 	}
 
-	token, err = a.createAuthToken(user.ID)
+	tokens, err = a.startSession(ctx, user.ID)
 	if err != nil {
-		return models.User{}, token, err
+		return models.User{}, tokens, err
 	}
 
-	return user, token, nil
+	return user, tokens, nil
 }
 
 func (a *JwtAuther) LoginUser(
 	ctx context.Context,
 	login string,
 	password string,
-) (models.User, types.RawToken, error) {
-	token := types.RawToken("")
+) (models.User, models.AuthTokens, error) {
+	var tokens models.AuthTokens
 	user, err := a.usersStorage.GetByLogin(ctx, login)
 	if err != nil {
 		if errors.Is(err, users.ErrUserNotFound) {
-			return models.User{}, token, ErrBadCredentials
+			return models.User{}, tokens, ErrBadCredentials
 		}
 
-		return models.User{}, token, err
+		return models.User{}, tokens, err
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Hash), []byte(password))
 	if err != nil {
-		return models.User{}, token, ErrBadCredentials
+		return models.User{}, tokens, ErrBadCredentials
+	}
+
+	if user.IsLocked {
+		return models.User{}, tokens, ErrUserLocked
+	}
+
+	a.upgradePasswordHash(ctx, user, password)
+
+	tokens, err = a.startSession(ctx, user.ID)
+	if err != nil {
+		return models.User{}, tokens, err
+	}
+
+	return user, tokens, nil
+}
+
+// ChangePassword отзывает все сессии пользователя, включая текущую,
+// и возвращает токены новой сессии для того, кто сменил пароль
+func (a *JwtAuther) ChangePassword(
+	ctx context.Context,
+	user models.User,
+	currentPassword string,
+	newPassword string,
+) (models.AuthTokens, error) {
+	var tokens models.AuthTokens
+
+	err := bcrypt.CompareHashAndPassword([]byte(user.Hash), []byte(currentPassword))
+	if err != nil {
+		return tokens, ErrBadCredentials
+	}
+
+	hash, err := a.hashPassword(newPassword)
+	if err != nil {
+		return tokens, err
 	}
 
-	token, err = a.createAuthToken(user.ID)
+	err = a.usersStorage.UpdatePasswordHash(ctx, user.ID, hash)
 	if err != nil {
-		return models.User{}, token, err
+		return tokens, err
+	}
+
+	err = a.sessionsStorage.RevokeAllByUser(ctx, user.ID)
+	if err != nil {
+		return tokens, err
+	}
+
+	return a.startSession(ctx, user.ID)
+}
+
+// RefreshTokens выдаёт новую пару токенов взамен refresh токена.
+// Старый refresh токен после этого становится недействительным.
+func (a *JwtAuther) RefreshTokens(ctx context.Context, refreshToken types.RawToken) (models.AuthTokens, error) {
+	var tokens models.AuthTokens
+
+	newRefreshToken, err := newRefreshToken()
+	if err != nil {
+		return tokens, err
+	}
+
+	session, err := a.sessionsStorage.Rotate(
+		ctx,
+		hashToken(refreshToken),
+		hashToken(newRefreshToken),
+		a.refreshTokenTTL,
+	)
+	if err != nil {
+		if errors.Is(err, sessions.ErrSessionNotFound) || errors.Is(err, sessions.ErrRefreshTokenReused) {
+			return tokens, ErrInvalidToken
+		}
+		return tokens, err
+	}
+
+	user, err := a.usersStorage.GetByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, users.ErrUserNotFound) {
+			return tokens, ErrInvalidToken
+		}
+		return tokens, err
 	}
 
-	return user, token, nil
+	if user.IsLocked {
+		return tokens, ErrUserLocked
+	}
+
+	return a.issueTokens(session, newRefreshToken)
+}
+
+// Logout отзывает сессию. Достаточно любого из токенов,
+// access токен при этом может быть уже просрочен.
+func (a *JwtAuther) Logout(ctx context.Context, accessToken types.RawToken, refreshToken types.RawToken) error {
+	var err error
+
+	if refreshToken != "" {
+		err = a.sessionsStorage.RevokeByRefreshToken(ctx, hashToken(refreshToken))
+	} else if accessToken != "" {
+		claims := &Claims{}
+		parser := jwt.NewParser(jwt.WithoutClaimsValidation(), jwt.WithValidMethods(a.keys.ValidMethods()))
+		_, parseErr := parser.ParseWithClaims(string(accessToken), claims, a.keys.KeyFunc)
+		if parseErr != nil || claims.SessionID == "" {
+			return nil
+		}
+
+		err = a.sessionsStorage.Revoke(ctx, claims.SessionID)
+	}
+
+	if err != nil && !errors.Is(err, sessions.ErrSessionNotFound) {
+		return err
+	}
+
+	return nil
 }
 
 func (a *JwtAuther) FromContext(ctx context.Context) (models.User, error) {
@@ -125,24 +264,135 @@ func (a *JwtAuther) FromContext(ctx context.Context) (models.User, error) {
 	return user, nil
 }
 
-func (a *JwtAuther) createAuthToken(userID int64) (types.RawToken, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
-		RegisteredClaims: jwt.RegisteredClaims{},
-		UserID:           userID,
+func (a *JwtAuther) hashPassword(password string) (types.PasswordHash, error) {
+	hashBytes, err := bcrypt.GenerateFromPassword([]byte(password), a.passwordCost)
+	if err != nil {
+		return types.PasswordHash(""), err
+	}
+	return types.PasswordHash(hashBytes), nil
+}
+
+// upgradePasswordHash перехеширует пароль, если хеш создан с меньшей стоимостью, чем сейчас в настройках.
+// Открытый пароль есть только при входе, поэтому обновлять хеши можно только здесь.
+func (a *JwtAuther) upgradePasswordHash(ctx context.Context, user models.User, password string) {
+	cost, err := bcrypt.Cost([]byte(user.Hash))
+	if err != nil || cost >= a.passwordCost {
+		return
+	}
+
+	hash, err := a.hashPassword(password)
+	if err == nil {
+		err = a.usersStorage.UpdatePasswordHash(ctx, user.ID, hash)
+	}
+	if err != nil {
+		a.logger.Warn("failed to upgrade password hash",
+			zap.Int64("user_id", user.ID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	a.logger.Info("password hash upgraded",
+		zap.Int64("user_id", user.ID),
+		zap.Int("from_cost", cost),
+		zap.Int("to_cost", a.passwordCost),
+	)
+}
+
+func (a *JwtAuther) startSession(ctx context.Context, userID int64) (models.AuthTokens, error) {
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	session, err := a.sessionsStorage.Create(ctx, userID, hashToken(refreshToken), a.refreshTokenTTL)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	return a.issueTokens(session, refreshToken)
+}
+
+func (a *JwtAuther) issueTokens(session models.Session, refreshToken types.RawToken) (models.AuthTokens, error) {
+	now := time.Now()
+
+	accessToken, accessTokenExpiresAt, err := a.createAuthToken(session, now)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	return models.AuthTokens{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessTokenExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: now.Add(a.refreshTokenTTL),
+	}, nil
+}
+
+func (a *JwtAuther) createAuthToken(session models.Session, now time.Time) (types.RawToken, time.Time, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return types.RawToken(""), time.Time{}, err
+	}
+
+	expiresAt := now.Add(a.accessTokenTTL)
+
+	tokenString, err := a.keys.Sign(Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		UserID:    session.UserID,
+		SessionID: session.ID,
 	})
+	if err != nil {
+		return types.RawToken(""), time.Time{}, err
+	}
+
+	return types.RawToken(tokenString), expiresAt, nil
+}
 
-	tokenString, err := token.SignedString([]byte(a.secretKey))
+func newRefreshToken() (types.RawToken, error) {
+	token, err := randomToken(32)
 	if err != nil {
 		return types.RawToken(""), err
 	}
+	return types.RawToken(token), nil
+}
+
+func randomToken(size int) (string, error) {
+	b := make([]byte, size)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
 
-	return types.RawToken(tokenString), nil
+// В БД хранятся только хеши токенов и ключей, чтобы утечка таблицы не давала доступ к аккаунтам
+func hashToken(token types.RawToken) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
 }
 
-func NewAuther(usersStorage users.Storage, secretKey string) *JwtAuther {
+func NewAuther(
+	usersStorage users.Storage,
+	sessionsStorage sessions.Storage,
+	keys *JwtKeys,
+	accessTokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+	passwordCost int,
+	logger *zap.Logger,
+) *JwtAuther {
 	return &JwtAuther{
-		usersStorage: usersStorage,
-		secretKey:    secretKey,
+		usersStorage:    usersStorage,
+		sessionsStorage: sessionsStorage,
+		keys:            keys,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+		passwordCost:    passwordCost,
+		logger:          logger,
 	}
 }
 
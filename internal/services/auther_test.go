@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/sessions"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestJwtAuther(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	user := models.User{
+		ID:    1,
+		Login: "admin",
+		Hash:  types.PasswordHash("hash"),
+	}
+	session := models.Session{
+		ID:     "session-id",
+		UserID: user.ID,
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		usersStorage := mocks.NewMockUsersStorage(ctrl)
+		sessionsStorage := mocks.NewMockSessionsStorage(ctrl)
+		auther := NewAuther(usersStorage, sessionsStorage, NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		token, expiresAt, err := auther.createAuthToken(session, time.Now())
+		require.NoError(t, err)
+		assert.True(t, expiresAt.After(time.Now()))
+
+		sessionsStorage.EXPECT().GetByID(gomock.Any(), session.ID).Return(session, nil)
+		usersStorage.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+
+		parsedUser, err := auther.ParseToken(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, user, parsedUser)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		auther := NewAuther(mocks.NewMockUsersStorage(ctrl), mocks.NewMockSessionsStorage(ctrl), NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		token, _, err := auther.createAuthToken(session, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		_, err = auther.ParseToken(context.Background(), token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("token without expiration", func(t *testing.T) {
+		auther := NewAuther(mocks.NewMockUsersStorage(ctrl), mocks.NewMockSessionsStorage(ctrl), NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: user.ID}).
+			SignedString([]byte("secretkey"))
+		require.NoError(t, err)
+
+		_, err = auther.ParseToken(context.Background(), types.RawToken(token))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("revoked session", func(t *testing.T) {
+		sessionsStorage := mocks.NewMockSessionsStorage(ctrl)
+		auther := NewAuther(mocks.NewMockUsersStorage(ctrl), sessionsStorage, NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		token, _, err := auther.createAuthToken(session, time.Now())
+		require.NoError(t, err)
+
+		revokedSession := session
+		revokedSession.RevokedAt = time.Now()
+		sessionsStorage.EXPECT().GetByID(gomock.Any(), session.ID).Return(revokedSession, nil)
+
+		_, err = auther.ParseToken(context.Background(), token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("refresh rotates token", func(t *testing.T) {
+		usersStorage := mocks.NewMockUsersStorage(ctrl)
+		sessionsStorage := mocks.NewMockSessionsStorage(ctrl)
+		auther := NewAuther(usersStorage, sessionsStorage, NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		oldToken := types.RawToken("old-refresh-token")
+		sessionsStorage.EXPECT().
+			Rotate(gomock.Any(), hashToken(oldToken), gomock.Any(), time.Hour).
+			Return(session, nil)
+		usersStorage.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+
+		tokens, err := auther.RefreshTokens(context.Background(), oldToken)
+		require.NoError(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.NotEqual(t, oldToken, tokens.RefreshToken)
+	})
+
+	t.Run("refresh token reused", func(t *testing.T) {
+		sessionsStorage := mocks.NewMockSessionsStorage(ctrl)
+		auther := NewAuther(mocks.NewMockUsersStorage(ctrl), sessionsStorage, NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		sessionsStorage.EXPECT().
+			Rotate(gomock.Any(), gomock.Any(), gomock.Any(), time.Hour).
+			Return(models.Session{}, sessions.ErrRefreshTokenReused)
+
+		_, err := auther.RefreshTokens(context.Background(), types.RawToken("stolen"))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("logout with expired access token", func(t *testing.T) {
+		sessionsStorage := mocks.NewMockSessionsStorage(ctrl)
+		auther := NewAuther(mocks.NewMockUsersStorage(ctrl), sessionsStorage, NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		token, _, err := auther.createAuthToken(session, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		sessionsStorage.EXPECT().Revoke(gomock.Any(), session.ID).Return(nil)
+
+		err = auther.Logout(context.Background(), token, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("logout with refresh token", func(t *testing.T) {
+		sessionsStorage := mocks.NewMockSessionsStorage(ctrl)
+		auther := NewAuther(mocks.NewMockUsersStorage(ctrl), sessionsStorage, NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		refreshToken := types.RawToken("refresh-token")
+		sessionsStorage.EXPECT().
+			RevokeByRefreshToken(gomock.Any(), hashToken(refreshToken)).
+			Return(sessions.ErrSessionNotFound)
+
+		err := auther.Logout(context.Background(), "", refreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("change password", func(t *testing.T) {
+		usersStorage := mocks.NewMockUsersStorage(ctrl)
+		sessionsStorage := mocks.NewMockSessionsStorage(ctrl)
+		auther := NewAuther(usersStorage, sessionsStorage, NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost, zap.NewExample())
+
+		hash, err := bcrypt.GenerateFromPassword([]byte("old-password1"), bcrypt.MinCost)
+		require.NoError(t, err)
+		userWithPassword := user
+		userWithPassword.Hash = types.PasswordHash(hash)
+
+		gomock.InOrder(
+			usersStorage.EXPECT().UpdatePasswordHash(gomock.Any(), user.ID, gomock.Any()).Return(nil),
+			sessionsStorage.EXPECT().RevokeAllByUser(gomock.Any(), user.ID).Return(nil),
+			sessionsStorage.EXPECT().Create(gomock.Any(), user.ID, gomock.Any(), time.Hour).Return(session, nil),
+		)
+
+		tokens, err := auther.ChangePassword(context.Background(), userWithPassword, "old-password1", "new-password1")
+		require.NoError(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+
+		_, err = auther.ChangePassword(context.Background(), userWithPassword, "wrong-password1", "new-password1")
+		assert.ErrorIs(t, err, ErrBadCredentials)
+	})
+
+	t.Run("login upgrades password hash cost", func(t *testing.T) {
+		usersStorage := mocks.NewMockUsersStorage(ctrl)
+		sessionsStorage := mocks.NewMockSessionsStorage(ctrl)
+		auther := NewAuther(usersStorage, sessionsStorage, NewHMACJwtKeys("secretkey"), time.Minute, time.Hour, bcrypt.MinCost+1, zap.NewExample())
+
+		hash, err := bcrypt.GenerateFromPassword([]byte("password1"), bcrypt.MinCost)
+		require.NoError(t, err)
+		userWithPassword := user
+		userWithPassword.Hash = types.PasswordHash(hash)
+
+		usersStorage.EXPECT().GetByLogin(gomock.Any(), user.Login).Return(userWithPassword, nil)
+		usersStorage.EXPECT().
+			UpdatePasswordHash(gomock.Any(), user.ID, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, id int64, newHash types.PasswordHash) error {
+				cost, err := bcrypt.Cost([]byte(newHash))
+				assert.NoError(t, err)
+				assert.Equal(t, bcrypt.MinCost+1, cost)
+				assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(newHash), []byte("password1")))
+				return nil
+			})
+		sessionsStorage.EXPECT().Create(gomock.Any(), user.ID, gomock.Any(), time.Hour).Return(session, nil)
+
+		_, _, err = auther.LoginUser(context.Background(), user.Login, "password1")
+		assert.NoError(t, err)
+	})
+}
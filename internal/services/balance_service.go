@@ -14,6 +14,12 @@ var (
 	ErrBalanceNotEnoughFunds = errors.New("not enough funds on user balance")
 	ErrBalanceBadPrecision   = errors.New("amount contains more than two digits after the dot")
 	ErrBalanceNegativeAmount = errors.New("cannot withdraw negative or zero amount")
+	ErrBalanceZeroAmount     = errors.New("cannot adjust balance by zero amount")
+
+	ErrWithdrawalNotFound        = errors.New("withdrawal not found")
+	ErrWithdrawalAlreadyRefunded = errors.New("withdrawal already refunded")
+	ErrWithdrawalAlreadyExists   = errors.New("withdrawal for order already exists")
+	ErrIdempotencyKeyReused      = errors.New("idempotency key already used for another withdrawal")
 )
 
 type OrderAdder interface {
@@ -26,21 +32,31 @@ type BalanceService struct {
 	logger         *zap.Logger
 }
 
+// Withdraw списывает баллы в счёт оплаты заказа.
+// Повтор запроса с тем же idempotencyKey возвращает исходный результат без повторного списания.
 func (b *BalanceService) Withdraw(
 	ctx context.Context,
 	orderNumber string,
-	sumRaw float64,
+	sum decimal.Decimal,
 	user models.User,
+	idempotencyKey string,
 ) error {
-	if sumRaw <= 0 {
+	if !sum.IsPositive() {
 		return ErrBalanceNegativeAmount
 	}
 
-	sum := decimal.NewFromFloat(sumRaw)
 	if !sum.Equal(sum.Truncate(2)) {
 		return ErrBalanceBadPrecision
 	}
 
+	replayed, err := b.isWithdrawalReplay(ctx, orderNumber, sum, user, idempotencyKey)
+	if err != nil {
+		return err
+	}
+	if replayed {
+		return nil
+	}
+
 	balance, err := b.GetBalance(ctx, user)
 	if err != nil {
 		return err
@@ -56,15 +72,30 @@ func (b *BalanceService) Withdraw(
 	}
 
 	withdraw := models.BalanceChange{
-		OrderNumber: order.OrderNumber,
-		UserID:      order.UserID,
-		Amount:      sum.Neg(),
+		OrderNumber:    order.OrderNumber,
+		UserID:         order.UserID,
+		Amount:         sum.Neg(),
+		IdempotencyKey: idempotencyKey,
 	}
 	err = b.balanceStorage.Withdraw(ctx, withdraw)
 	if err != nil {
 		if errors.Is(err, balancePackage.ErrNotEnoughFunds) {
 			return ErrBalanceNotEnoughFunds
 		}
+		if errors.Is(err, balancePackage.ErrIdempotencyKeyUsed) {
+			return ErrIdempotencyKeyReused
+		}
+		if errors.Is(err, balancePackage.ErrWithdrawalExists) {
+			// параллельный повтор того же запроса мог успеть записать списание раньше
+			replayed, err := b.isWithdrawalReplay(ctx, orderNumber, sum, user, idempotencyKey)
+			if err != nil {
+				return err
+			}
+			if replayed {
+				return nil
+			}
+			return ErrWithdrawalAlreadyExists
+		}
 
 		b.logger.Error("failed to add order for withdrawal", zap.Error(err))
 		return err
@@ -73,6 +104,120 @@ func (b *BalanceService) Withdraw(
 	return nil
 }
 
+// isWithdrawalReplay проверяет, было ли уже списание за заказ.
+// Повтор с тем же ключом и суммой считается успешным, любое другое списание за заказ - конфликт.
+func (b *BalanceService) isWithdrawalReplay(
+	ctx context.Context,
+	orderNumber string,
+	sum decimal.Decimal,
+	user models.User,
+	idempotencyKey string,
+) (bool, error) {
+	withdrawal, err := b.balanceStorage.GetOrderWithdrawal(ctx, orderNumber)
+	if err != nil {
+		if errors.Is(err, balancePackage.ErrWithdrawalNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if idempotencyKey != "" &&
+		withdrawal.UserID == user.ID &&
+		withdrawal.IdempotencyKey == idempotencyKey &&
+		withdrawal.Amount.Equal(sum.Neg()) {
+		return true, nil
+	}
+
+	return false, ErrWithdrawalAlreadyExists
+}
+
+// Adjust вручную начисляет или списывает баллы от имени оператора
+func (b *BalanceService) Adjust(
+	ctx context.Context,
+	user models.User,
+	amount decimal.Decimal,
+	reason string,
+	reference string,
+	operator models.User,
+) error {
+	if amount.IsZero() {
+		return ErrBalanceZeroAmount
+	}
+
+	if !amount.Equal(amount.Truncate(2)) {
+		return ErrBalanceBadPrecision
+	}
+
+	adjustment := models.BalanceChange{
+		UserID:     user.ID,
+		Amount:     amount,
+		Reason:     reason,
+		Reference:  reference,
+		OperatorID: operator.ID,
+	}
+	err := b.balanceStorage.Adjust(ctx, adjustment)
+	if err != nil {
+		if errors.Is(err, balancePackage.ErrNotEnoughFunds) {
+			return ErrBalanceNotEnoughFunds
+		}
+
+		b.logger.Error("failed to adjust balance", zap.Error(err))
+		return err
+	}
+
+	b.logger.Info("balance adjusted by operator",
+		zap.Int64("user_id", user.ID),
+		zap.Int64("operator_id", operator.ID),
+		zap.String("amount", amount.String()),
+		zap.String("reference", reference),
+	)
+
+	return nil
+}
+
+func (b *BalanceService) GetAdjustments(
+	ctx context.Context,
+	user models.User,
+) ([]models.BalanceChange, error) {
+	return b.balanceStorage.GetAdjustments(ctx, user)
+}
+
+// Refund возвращает пользователю баллы, списанные в счёт отменённого заказа
+func (b *BalanceService) Refund(
+	ctx context.Context,
+	user models.User,
+	orderNumber string,
+	reason string,
+	operator models.User,
+) error {
+	refund, err := b.balanceStorage.Refund(ctx, models.BalanceChange{
+		OrderNumber: orderNumber,
+		UserID:      user.ID,
+		Reason:      reason,
+		OperatorID:  operator.ID,
+	})
+	if err != nil {
+		if errors.Is(err, balancePackage.ErrWithdrawalNotFound) {
+			return ErrWithdrawalNotFound
+		}
+		if errors.Is(err, balancePackage.ErrAlreadyRefunded) {
+			return ErrWithdrawalAlreadyRefunded
+		}
+
+		b.logger.Error("failed to refund withdrawal", zap.Error(err))
+		return err
+	}
+
+	b.logger.Info("withdrawal refunded",
+		zap.Int64("user_id", user.ID),
+		zap.Int64("operator_id", operator.ID),
+		zap.String("order_number", orderNumber),
+		zap.String("amount", refund.Amount.String()),
+	)
+
+	return nil
+}
+
 func (b *BalanceService) GetBalance(
 	ctx context.Context,
 	user models.User,
@@ -83,8 +228,79 @@ func (b *BalanceService) GetBalance(
 func (b *BalanceService) GetWithdrawals(
 	ctx context.Context,
 	user models.User,
-) ([]models.BalanceChange, error) {
-	return b.balanceStorage.GetWithdrawals(ctx, user)
+	filter models.WithdrawalsFilter,
+) (models.WithdrawalsPage, error) {
+	pageSize := filter.Limit
+
+	// лишнее списание показывает, что есть следующая страница
+	if pageSize > 0 {
+		filter.Limit = pageSize + 1
+	}
+
+	withdrawals, err := b.balanceStorage.GetWithdrawals(ctx, user, filter)
+	if err != nil {
+		return models.WithdrawalsPage{}, err
+	}
+
+	summary, err := b.balanceStorage.GetWithdrawalsSummary(ctx, user, filter)
+	if err != nil {
+		return models.WithdrawalsPage{}, err
+	}
+
+	page := models.WithdrawalsPage{
+		Withdrawals: withdrawals,
+		Summary:     summary,
+	}
+
+	if pageSize > 0 && len(withdrawals) > pageSize {
+		page.Withdrawals = withdrawals[:pageSize]
+
+		last := page.Withdrawals[pageSize-1]
+		page.Next = models.PageCursor{
+			At:  last.ProcessedAt,
+			Key: last.ID,
+		}
+	}
+
+	return page, nil
+}
+
+func (b *BalanceService) GetHistory(
+	ctx context.Context,
+	user models.User,
+	filter models.BalanceHistoryFilter,
+) (models.BalanceHistoryPage, error) {
+	pageSize := filter.Limit
+
+	// лишняя запись показывает, что есть следующая страница
+	filter.Limit = pageSize + 1
+
+	entries, err := b.balanceStorage.GetHistory(ctx, user, filter)
+	if err != nil {
+		return models.BalanceHistoryPage{}, err
+	}
+
+	total, err := b.balanceStorage.CountHistory(ctx, user, filter)
+	if err != nil {
+		return models.BalanceHistoryPage{}, err
+	}
+
+	page := models.BalanceHistoryPage{
+		Entries: entries,
+		Total:   total,
+	}
+
+	if len(entries) > pageSize {
+		page.Entries = entries[:pageSize]
+
+		last := page.Entries[pageSize-1]
+		page.Next = models.PageCursor{
+			At:  last.ProcessedAt,
+			Key: last.ID,
+		}
+	}
+
+	return page, nil
 }
 
 func NewBalancer(
@@ -6,7 +6,9 @@ import (
 
 	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	balancePackage "github.com/aleksandrpnshkn/gophermart/internal/storage/balance"
 	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
@@ -29,7 +31,7 @@ func TestBalancer(t *testing.T) {
 
 		balancer := NewBalancer(ordersService, balanceStorage, logger)
 
-		err := balancer.Withdraw(context.Background(), "123", 123.123, user)
+		err := balancer.Withdraw(context.Background(), "123", decimal.RequireFromString("123.123"), user, "")
 
 		assert.ErrorIs(t, err, ErrBalanceBadPrecision)
 	})
@@ -40,8 +42,120 @@ func TestBalancer(t *testing.T) {
 
 		balancer := NewBalancer(ordersService, balanceStorage, logger)
 
-		err := balancer.Withdraw(context.Background(), "123", -123, user)
+		err := balancer.Withdraw(context.Background(), "123", decimal.NewFromInt(-123), user, "")
 
 		assert.ErrorIs(t, err, ErrBalanceNegativeAmount)
 	})
+	t.Run("sum smaller than a cent", func(t *testing.T) {
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		ordersService := mocks.NewMockOrdersService(ctrl)
+
+		balancer := NewBalancer(ordersService, balanceStorage, logger)
+
+		// во float 0.30000000000000004, лишний знак не должен теряться при округлении
+		err := balancer.Withdraw(context.Background(), "123", decimal.RequireFromString("0.30000000000000004"), user, "")
+
+		assert.ErrorIs(t, err, ErrBalanceBadPrecision)
+	})
+
+	t.Run("withdraw whole balance accrued in parts", func(t *testing.T) {
+		sum := decimal.RequireFromString("0.3")
+		withdrawal := models.BalanceChange{
+			OrderNumber: "2377225624",
+			UserID:      user.ID,
+			Amount:      sum.Neg(),
+		}
+
+		ordersService := mocks.NewMockOrdersService(ctrl)
+		ordersService.EXPECT().
+			Add(gomock.Any(), "2377225624", user).
+			Return(models.Order{OrderNumber: "2377225624", UserID: user.ID}, nil)
+
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		balanceStorage.EXPECT().
+			GetOrderWithdrawal(gomock.Any(), "2377225624").
+			Return(models.BalanceChange{}, balancePackage.ErrWithdrawalNotFound)
+		balanceStorage.EXPECT().
+			GetBalance(gomock.Any(), user).
+			Return(models.Balance{Current: decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2"))}, nil)
+		balanceStorage.EXPECT().
+			Withdraw(gomock.Any(), withdrawal).
+			Return(nil)
+
+		balancer := NewBalancer(ordersService, balanceStorage, logger)
+
+		err := balancer.Withdraw(context.Background(), "2377225624", sum, user, "")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("zero adjustment", func(t *testing.T) {
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		ordersService := mocks.NewMockOrdersService(ctrl)
+
+		balancer := NewBalancer(ordersService, balanceStorage, logger)
+
+		err := balancer.Adjust(context.Background(), user, decimal.Zero, "reason", "", user)
+
+		assert.ErrorIs(t, err, ErrBalanceZeroAmount)
+	})
+
+	t.Run("adjustment with bad precision", func(t *testing.T) {
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		ordersService := mocks.NewMockOrdersService(ctrl)
+
+		balancer := NewBalancer(ordersService, balanceStorage, logger)
+
+		err := balancer.Adjust(context.Background(), user, decimal.RequireFromString("1.234"), "reason", "", user)
+
+		assert.ErrorIs(t, err, ErrBalanceBadPrecision)
+	})
+	t.Run("refund twice", func(t *testing.T) {
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		balanceStorage.EXPECT().
+			Refund(gomock.Any(), gomock.Any()).
+			Return(models.BalanceChange{}, balancePackage.ErrAlreadyRefunded)
+		ordersService := mocks.NewMockOrdersService(ctrl)
+
+		balancer := NewBalancer(ordersService, balanceStorage, logger)
+
+		err := balancer.Refund(context.Background(), user, "123", "reason", user)
+
+		assert.ErrorIs(t, err, ErrWithdrawalAlreadyRefunded)
+	})
+	t.Run("concurrent retry of withdrawal", func(t *testing.T) {
+		withdrawal := models.BalanceChange{
+			OrderNumber:    "2377225624",
+			UserID:         user.ID,
+			Amount:         decimal.NewFromInt(10).Neg(),
+			IdempotencyKey: "key",
+		}
+
+		ordersService := mocks.NewMockOrdersService(ctrl)
+		ordersService.EXPECT().
+			Add(gomock.Any(), "2377225624", user).
+			Return(models.Order{OrderNumber: "2377225624", UserID: user.ID}, nil)
+
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		gomock.InOrder(
+			balanceStorage.EXPECT().
+				GetOrderWithdrawal(gomock.Any(), "2377225624").
+				Return(models.BalanceChange{}, balancePackage.ErrWithdrawalNotFound),
+			balanceStorage.EXPECT().
+				GetBalance(gomock.Any(), user).
+				Return(models.Balance{Current: decimal.NewFromInt(100)}, nil),
+			balanceStorage.EXPECT().
+				Withdraw(gomock.Any(), withdrawal).
+				Return(balancePackage.ErrWithdrawalExists),
+			balanceStorage.EXPECT().
+				GetOrderWithdrawal(gomock.Any(), "2377225624").
+				Return(withdrawal, nil),
+		)
+
+		balancer := NewBalancer(ordersService, balanceStorage, logger)
+
+		err := balancer.Withdraw(context.Background(), "2377225624", decimal.NewFromInt(10), user, "key")
+
+		assert.NoError(t, err)
+	})
 }
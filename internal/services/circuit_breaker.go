@@ -0,0 +1,136 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type CircuitState string
+
+const (
+	// запросы идут как обычно
+	CircuitStateClosed CircuitState = "closed"
+
+	// запросы не отправляются до истечения openTimeout
+	CircuitStateOpen CircuitState = "open"
+
+	// пропускается один пробный запрос, по его результату цепь закрывается или снова открывается
+	CircuitStateHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreaker перестаёт ходить в сервис после failureThreshold ошибок подряд,
+// чтобы воркеры не тратили время на заведомо неудачные запросы
+type CircuitBreaker struct {
+	mu sync.Mutex
+
+	name             string
+	failureThreshold int
+	openTimeout      time.Duration
+	logger           *zap.Logger
+	now              func() time.Time
+
+	state         CircuitState
+	failures      int
+	openedUntil   time.Time
+	probeInFlight bool
+}
+
+// Allow возвращает false и оставшееся время, если запрос отправлять нельзя
+func (b *CircuitBreaker) Allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitStateOpen:
+		now := b.now()
+		if b.openedUntil.After(now) {
+			return false, b.openedUntil.Sub(now)
+		}
+
+		b.setState(CircuitStateHalfOpen)
+		b.probeInFlight = true
+		return true, 0
+	case CircuitStateHalfOpen:
+		if b.probeInFlight {
+			return false, b.openTimeout
+		}
+
+		b.probeInFlight = true
+		return true, 0
+	default:
+		return true, 0
+	}
+}
+
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probeInFlight = false
+	if b.state != CircuitStateClosed {
+		b.setState(CircuitStateClosed)
+	}
+}
+
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probeInFlight = false
+
+	if b.state == CircuitStateHalfOpen || b.failures >= b.failureThreshold {
+		b.openedUntil = b.now().Add(b.openTimeout)
+		if b.state != CircuitStateOpen {
+			b.setState(CircuitStateOpen)
+		}
+	}
+}
+
+// Release снимает пробный запрос, результат которого ничего не говорит о сервисе
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probeInFlight = false
+}
+
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// открытая цепь, у которой истёк таймаут, пропустит следующий запрос
+	if b.state == CircuitStateOpen && !b.openedUntil.After(b.now()) {
+		return CircuitStateHalfOpen
+	}
+	return b.state
+}
+
+func (b *CircuitBreaker) setState(state CircuitState) {
+	b.logger.Warn("circuit breaker state changed",
+		zap.String("circuit", b.name),
+		zap.String("from", string(b.state)),
+		zap.String("to", string(state)),
+		zap.Int("failures", b.failures),
+	)
+	b.state = state
+}
+
+func NewCircuitBreaker(
+	name string,
+	failureThreshold int,
+	openTimeout time.Duration,
+	logger *zap.Logger,
+) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		logger:           logger,
+		now:              time.Now,
+		state:            CircuitStateClosed,
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	logger := zap.NewExample()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	newBreaker := func() *CircuitBreaker {
+		breaker := NewCircuitBreaker("accrual", 3, 30*time.Second, logger)
+		breaker.now = func() time.Time {
+			return now
+		}
+		return breaker
+	}
+
+	t.Run("opens after consecutive failures", func(t *testing.T) {
+		breaker := newBreaker()
+
+		breaker.Failure()
+		breaker.Failure()
+		breaker.Success()
+		breaker.Failure()
+		breaker.Failure()
+		assert.Equal(t, CircuitStateClosed, breaker.State())
+
+		breaker.Failure()
+		assert.Equal(t, CircuitStateOpen, breaker.State())
+
+		allowed, openFor := breaker.Allow()
+		assert.False(t, allowed)
+		assert.Equal(t, 30*time.Second, openFor)
+	})
+
+	t.Run("single probe after timeout", func(t *testing.T) {
+		breaker := newBreaker()
+		for i := 0; i < 3; i++ {
+			breaker.Failure()
+		}
+
+		now = now.Add(31 * time.Second)
+		assert.Equal(t, CircuitStateHalfOpen, breaker.State())
+
+		allowed, _ := breaker.Allow()
+		assert.True(t, allowed)
+
+		allowed, _ = breaker.Allow()
+		assert.False(t, allowed, "second request while probe in flight")
+
+		breaker.Success()
+		assert.Equal(t, CircuitStateClosed, breaker.State())
+
+		allowed, _ = breaker.Allow()
+		assert.True(t, allowed)
+	})
+
+	t.Run("failed probe opens again", func(t *testing.T) {
+		breaker := newBreaker()
+		for i := 0; i < 3; i++ {
+			breaker.Failure()
+		}
+
+		now = now.Add(31 * time.Second)
+		allowed, _ := breaker.Allow()
+		assert.True(t, allowed)
+
+		breaker.Failure()
+		assert.Equal(t, CircuitStateOpen, breaker.State())
+
+		allowed, openFor := breaker.Allow()
+		assert.False(t, allowed)
+		assert.Equal(t, 30*time.Second, openFor)
+	})
+
+	t.Run("released probe", func(t *testing.T) {
+		breaker := newBreaker()
+		for i := 0; i < 3; i++ {
+			breaker.Failure()
+		}
+
+		now = now.Add(31 * time.Second)
+		allowed, _ := breaker.Allow()
+		assert.True(t, allowed)
+
+		breaker.Release()
+
+		allowed, _ = breaker.Allow()
+		assert.True(t, allowed)
+	})
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/jobs"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+)
+
+// DeadLetterService даёт операторам разобрать задачи, которые очередь не смогла обработать
+type DeadLetterService struct {
+	jobsStorage jobs.Storage
+	logger      *zap.Logger
+}
+
+func (d *DeadLetterService) GetDeadLetters(ctx context.Context, limit int) ([]models.OrderJob, error) {
+	return d.jobsStorage.GetFailed(ctx, limit)
+}
+
+func (d *DeadLetterService) GetDeadLetter(
+	ctx context.Context,
+	orderNumber string,
+	jobName string,
+) (models.OrderJob, error) {
+	job, err := d.jobsStorage.GetFailedJob(ctx, orderNumber, jobName)
+	if err != nil {
+		if errors.Is(err, jobs.ErrJobNotFound) {
+			return models.OrderJob{}, ErrDeadLetterNotFound
+		}
+		return models.OrderJob{}, err
+	}
+
+	return job, nil
+}
+
+func (d *DeadLetterService) Requeue(ctx context.Context, orderNumber string, jobName string) error {
+	err := d.jobsStorage.Requeue(ctx, orderNumber, jobName)
+	if err != nil {
+		if errors.Is(err, jobs.ErrJobNotFound) {
+			return ErrDeadLetterNotFound
+		}
+		return err
+	}
+
+	d.logger.Info("dead letter requeued",
+		zap.String("order_number", orderNumber),
+		zap.String("job_name", jobName),
+	)
+
+	return nil
+}
+
+func (d *DeadLetterService) Discard(ctx context.Context, orderNumber string, jobName string) error {
+	err := d.jobsStorage.Discard(ctx, orderNumber, jobName)
+	if err != nil {
+		if errors.Is(err, jobs.ErrJobNotFound) {
+			return ErrDeadLetterNotFound
+		}
+		return err
+	}
+
+	d.logger.Info("dead letter discarded",
+		zap.String("order_number", orderNumber),
+		zap.String("job_name", jobName),
+	)
+
+	return nil
+}
+
+func NewDeadLetterService(jobsStorage jobs.Storage, logger *zap.Logger) *DeadLetterService {
+	return &DeadLetterService{
+		jobsStorage: jobsStorage,
+		logger:      logger,
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
 )
@@ -28,9 +29,11 @@ func (p *GetAccrualProcessor) Process(
 			}
 		}
 
+		// accrual мог ещё не узнать о заказе, поэтому 204 тоже повторяем, пока не кончатся попытки
 		if errors.Is(err, ErrAccrualNotProcessedStatus) ||
-			errors.Is(err, ErrAccrualFailedToGet) {
-			return order, ErrJobRetry
+			errors.Is(err, ErrAccrualFailedToGet) ||
+			errors.Is(err, ErrAccrualOrderNotFound) {
+			return order, fmt.Errorf("%w: %w", ErrJobRetry, err)
 		}
 
 		return order, err
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/responses"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	ErrUnsupportedJwtKey = errors.New("unsupported jwt key type")
+	ErrUnknownJwtKey     = errors.New("unknown jwt key id")
+)
+
+type jwtKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// JwtKeys ключи подписи токенов. Подписывается всегда одним ключом,
+// а проверять можно любым из активных, чтобы ротация не разлогинивала пользователей.
+type JwtKeys struct {
+	signing *jwtKey
+	verify  map[string]*jwtKey
+}
+
+func (k *JwtKeys) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	if k.signing.id != "" {
+		token.Header["kid"] = k.signing.id
+	}
+
+	return token.SignedString(k.signing.signKey)
+}
+
+// KeyFunc подбирает ключ проверки по заголовку kid
+func (k *JwtKeys) KeyFunc(token *jwt.Token) (interface{}, error) {
+	// Общий секрет без kid, оставлен для локального запуска
+	if k.signing.id == "" {
+		return k.signing.verifyKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.verify[kid]
+	if !ok {
+		return nil, ErrUnknownJwtKey
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, ErrUnknownJwtKey
+	}
+
+	return key.verifyKey, nil
+}
+
+func (k *JwtKeys) ValidMethods() []string {
+	methods := []string{}
+	seen := map[string]bool{}
+	for _, key := range k.verify {
+		alg := key.method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// JWKs публичные ключи для проверки токенов другими сервисами.
+// Для общего секрета список пуст.
+func (k *JwtKeys) JWKs() []responses.JWK {
+	jwks := []responses.JWK{}
+
+	if k.signing.id == "" {
+		return jwks
+	}
+
+	// Ключ подписи первым, остальные по kid, чтобы ответ не менялся между запросами
+	jwks = append(jwks, newJWK(k.signing))
+
+	ids := []string{}
+	for id := range k.verify {
+		if id != k.signing.id {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		jwks = append(jwks, newJWK(k.verify[id]))
+	}
+
+	return jwks
+}
+
+func NewHMACJwtKeys(secretKey string) *JwtKeys {
+	key := &jwtKey{
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secretKey),
+		verifyKey: []byte(secretKey),
+	}
+
+	return &JwtKeys{
+		signing: key,
+		verify: map[string]*jwtKey{
+			"": key,
+		},
+	}
+}
+
+// NewJwtKeys принимает приватный ключ подписи RSA или Ed25519
+// и публичные ключи, которые ещё нужно принимать после ротации
+func NewJwtKeys(signKey crypto.Signer, verifyKeys ...crypto.PublicKey) (*JwtKeys, error) {
+	signing, err := newJwtKey(signKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	signing.signKey = signKey
+
+	keys := JwtKeys{
+		signing: signing,
+		verify: map[string]*jwtKey{
+			signing.id: signing,
+		},
+	}
+
+	for _, verifyKey := range verifyKeys {
+		key, err := newJwtKey(verifyKey)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := keys.verify[key.id]; !ok {
+			keys.verify[key.id] = key
+		}
+	}
+
+	return &keys, nil
+}
+
+// LoadJwtKeys читает ключи из PEM файлов. Для проверки подходят
+// как публичные, так и приватные ключи.
+func LoadJwtKeys(signingKeyFile string, verifyKeyFiles []string) (*JwtKeys, error) {
+	rawSigningKey, err := readPEMKey(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	signKey, ok := rawSigningKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s: private key expected", signingKeyFile)
+	}
+
+	verifyKeys := []crypto.PublicKey{}
+	for _, verifyKeyFile := range verifyKeyFiles {
+		rawVerifyKey, err := readPEMKey(verifyKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if signer, ok := rawVerifyKey.(crypto.Signer); ok {
+			rawVerifyKey = signer.Public()
+		}
+		verifyKeys = append(verifyKeys, rawVerifyKey)
+	}
+
+	return NewJwtKeys(signKey, verifyKeys...)
+}
+
+func readPEMKey(path string) (interface{}, error) {
+	rawPEM, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(rawPEM)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", path)
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: unsupported PEM block %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+func newJwtKey(publicKey crypto.PublicKey) (*jwtKey, error) {
+	key := jwtKey{
+		verifyKey: publicKey,
+	}
+
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, ErrUnsupportedJwtKey
+	}
+
+	thumbprint, err := jwkThumbprint(newJWK(&key))
+	if err != nil {
+		return nil, err
+	}
+	key.id = thumbprint
+
+	return &key, nil
+}
+
+func newJWK(key *jwtKey) responses.JWK {
+	jwk := responses.JWK{
+		KeyID:     key.id,
+		Use:       "sig",
+		Algorithm: key.method.Alg(),
+	}
+
+	switch publicKey := key.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.Modulus = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+
+	return jwk
+}
+
+// jwkThumbprint kid по RFC 7638, чтобы он не зависел от имени файла
+func jwkThumbprint(jwk responses.JWK) (string, error) {
+	var members interface{}
+	switch jwk.KeyType {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.Exponent, jwk.KeyType, jwk.Modulus}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X}
+	default:
+		return "", ErrUnsupportedJwtKey
+	}
+
+	rawMembers, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(rawMembers)
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
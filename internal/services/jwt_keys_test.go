@@ -0,0 +1,113 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJwtKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	parse := func(keys *JwtKeys, token string) error {
+		_, err := jwt.ParseWithClaims(token, &Claims{}, keys.KeyFunc, jwt.WithValidMethods(keys.ValidMethods()))
+		return err
+	}
+
+	t.Run("rs256", func(t *testing.T) {
+		keys, err := NewJwtKeys(rsaKey)
+		require.NoError(t, err)
+
+		token, err := keys.Sign(Claims{UserID: 1})
+		require.NoError(t, err)
+
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+		require.NoError(t, err)
+		assert.Equal(t, "RS256", parsed.Method.Alg())
+		assert.Equal(t, keys.signing.id, parsed.Header["kid"])
+
+		assert.NoError(t, parse(keys, token))
+	})
+
+	t.Run("rotation keeps previous key valid", func(t *testing.T) {
+		oldKeys, err := NewJwtKeys(rsaKey)
+		require.NoError(t, err)
+		oldToken, err := oldKeys.Sign(Claims{UserID: 1})
+		require.NoError(t, err)
+
+		newKeys, err := NewJwtKeys(edKey, rsaKey.Public())
+		require.NoError(t, err)
+		newToken, err := newKeys.Sign(Claims{UserID: 1})
+		require.NoError(t, err)
+
+		assert.NoError(t, parse(newKeys, oldToken))
+		assert.NoError(t, parse(newKeys, newToken))
+		assert.Error(t, parse(oldKeys, newToken))
+
+		jwks := newKeys.JWKs()
+		require.Len(t, jwks, 2)
+		assert.Equal(t, "OKP", jwks[0].KeyType)
+		assert.Equal(t, "EdDSA", jwks[0].Algorithm)
+		assert.Equal(t, "RSA", jwks[1].KeyType)
+		assert.Equal(t, "AQAB", jwks[1].Exponent)
+	})
+
+	t.Run("shared secret rejected when keys configured", func(t *testing.T) {
+		keys, err := NewJwtKeys(edKey)
+		require.NoError(t, err)
+
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: 1})
+		token.Header["kid"] = keys.signing.id
+		forged, err := token.SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		assert.Error(t, parse(keys, forged))
+	})
+
+	t.Run("shared secret", func(t *testing.T) {
+		keys := NewHMACJwtKeys("secretkey")
+
+		token, err := keys.Sign(Claims{UserID: 1})
+		require.NoError(t, err)
+
+		assert.NoError(t, parse(keys, token))
+		assert.Error(t, parse(NewHMACJwtKeys("other"), token))
+		assert.Empty(t, keys.JWKs())
+	})
+
+	t.Run("load from PEM files", func(t *testing.T) {
+		dir := t.TempDir()
+
+		rawEdKey, err := x509.MarshalPKCS8PrivateKey(edKey)
+		require.NoError(t, err)
+		signingKeyFile := filepath.Join(dir, "signing.pem")
+		err = os.WriteFile(signingKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: rawEdKey}), 0600)
+		require.NoError(t, err)
+
+		rawRSAKey, err := x509.MarshalPKIXPublicKey(rsaKey.Public())
+		require.NoError(t, err)
+		verifyKeyFile := filepath.Join(dir, "previous.pem")
+		err = os.WriteFile(verifyKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rawRSAKey}), 0600)
+		require.NoError(t, err)
+
+		keys, err := LoadJwtKeys(signingKeyFile, []string{verifyKeyFile})
+		require.NoError(t, err)
+		assert.Len(t, keys.JWKs(), 2)
+
+		_, err = LoadJwtKeys(verifyKeyFile, nil)
+		assert.Error(t, err)
+	})
+}
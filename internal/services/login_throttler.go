@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/loginattempts"
+)
+
+// LoginThrottlePolicy первые FreeFailures ошибок бесплатны,
+// дальше каждая следующая удваивает блокировку вплоть до MaxLockout
+type LoginThrottlePolicy struct {
+	FreeFailures int
+	BaseDelay    time.Duration
+	MaxLockout   time.Duration
+}
+
+func (p LoginThrottlePolicy) Lockout(failures int) time.Duration {
+	if failures <= p.FreeFailures || p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeFailures + 1; i < failures && delay < p.MaxLockout; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxLockout {
+		delay = p.MaxLockout
+	}
+
+	return delay
+}
+
+// LoginThrottler ограничивает подбор паролей. Счётчики ведутся отдельно по логину
+// (подбор пароля к одному аккаунту) и по IP (перебор аккаунтов с одного адреса).
+type LoginThrottler struct {
+	storage loginattempts.Storage
+
+	loginPolicy LoginThrottlePolicy
+	ipPolicy    LoginThrottlePolicy
+
+	// через сколько после последней ошибки счётчик обнуляется
+	window time.Duration
+}
+
+// BlockedFor возвращает, сколько ещё нельзя пытаться войти, 0 - можно
+func (t *LoginThrottler) BlockedFor(ctx context.Context, login string, ip string) (time.Duration, error) {
+	return t.storage.GetBlockedFor(ctx, []models.LoginAttemptKey{
+		loginKey(login),
+		ipKey(ip),
+	})
+}
+
+func (t *LoginThrottler) RegisterFailure(ctx context.Context, login string, ip string) error {
+	err := t.registerFailure(ctx, loginKey(login), t.loginPolicy)
+	if err != nil {
+		return err
+	}
+
+	return t.registerFailure(ctx, ipKey(ip), t.ipPolicy)
+}
+
+// RegisterSuccess сбрасывает только счётчик логина: иначе один свой аккаунт
+// позволял бы бесконечно обнулять счётчик IP при переборе чужих
+func (t *LoginThrottler) RegisterSuccess(ctx context.Context, login string) error {
+	return t.storage.Reset(ctx, loginKey(login))
+}
+
+func (t *LoginThrottler) registerFailure(
+	ctx context.Context,
+	key models.LoginAttemptKey,
+	policy LoginThrottlePolicy,
+) error {
+	failures, err := t.storage.AddFailure(ctx, key, t.window)
+	if err != nil {
+		return err
+	}
+
+	lockout := policy.Lockout(failures)
+	if lockout == 0 {
+		return nil
+	}
+
+	return t.storage.Block(ctx, key, lockout)
+}
+
+func loginKey(login string) models.LoginAttemptKey {
+	return models.LoginAttemptKey{
+		Kind:  models.LoginAttemptKindLogin,
+		Value: login,
+	}
+}
+
+func ipKey(ip string) models.LoginAttemptKey {
+	return models.LoginAttemptKey{
+		Kind:  models.LoginAttemptKindIP,
+		Value: ip,
+	}
+}
+
+func NewLoginThrottler(
+	storage loginattempts.Storage,
+	loginPolicy LoginThrottlePolicy,
+	ipPolicy LoginThrottlePolicy,
+	window time.Duration,
+) *LoginThrottler {
+	return &LoginThrottler{
+		storage:     storage,
+		loginPolicy: loginPolicy,
+		ipPolicy:    ipPolicy,
+		window:      window,
+	}
+}
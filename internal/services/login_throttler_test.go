@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoginThrottlePolicy(t *testing.T) {
+	policy := LoginThrottlePolicy{
+		FreeFailures: 3,
+		BaseDelay:    time.Second,
+		MaxLockout:   10 * time.Second,
+	}
+
+	tests := []struct {
+		failures int
+		lockout  time.Duration
+	}{
+		{failures: 1, lockout: 0},
+		{failures: 3, lockout: 0},
+		{failures: 4, lockout: time.Second},
+		{failures: 5, lockout: 2 * time.Second},
+		{failures: 7, lockout: 8 * time.Second},
+		{failures: 8, lockout: 10 * time.Second},
+		{failures: 1000, lockout: 10 * time.Second},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.lockout, policy.Lockout(test.failures), "failures %d", test.failures)
+	}
+}
+
+func TestLoginThrottler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	loginPolicy := LoginThrottlePolicy{FreeFailures: 2, BaseDelay: time.Second, MaxLockout: time.Minute}
+	ipPolicy := LoginThrottlePolicy{FreeFailures: 10, BaseDelay: time.Second, MaxLockout: time.Minute}
+
+	login := models.LoginAttemptKey{Kind: models.LoginAttemptKindLogin, Value: "admin"}
+	ip := models.LoginAttemptKey{Kind: models.LoginAttemptKindIP, Value: "192.0.2.1"}
+
+	t.Run("blocked", func(t *testing.T) {
+		storage := mocks.NewMockLoginAttemptsStorage(ctrl)
+		storage.EXPECT().
+			GetBlockedFor(gomock.Any(), []models.LoginAttemptKey{login, ip}).
+			Return(30*time.Second, nil)
+
+		throttler := NewLoginThrottler(storage, loginPolicy, ipPolicy, time.Hour)
+
+		blockedFor, err := throttler.BlockedFor(context.Background(), "admin", "192.0.2.1")
+		assert.NoError(t, err)
+		assert.Equal(t, 30*time.Second, blockedFor)
+	})
+
+	t.Run("login locked out after free failures", func(t *testing.T) {
+		storage := mocks.NewMockLoginAttemptsStorage(ctrl)
+		storage.EXPECT().AddFailure(gomock.Any(), login, time.Hour).Return(4, nil)
+		storage.EXPECT().Block(gomock.Any(), login, 2*time.Second).Return(nil)
+		storage.EXPECT().AddFailure(gomock.Any(), ip, time.Hour).Return(4, nil)
+
+		throttler := NewLoginThrottler(storage, loginPolicy, ipPolicy, time.Hour)
+
+		err := throttler.RegisterFailure(context.Background(), "admin", "192.0.2.1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("success resets only login", func(t *testing.T) {
+		storage := mocks.NewMockLoginAttemptsStorage(ctrl)
+		storage.EXPECT().Reset(gomock.Any(), login).Return(nil)
+
+		throttler := NewLoginThrottler(storage, loginPolicy, ipPolicy, time.Hour)
+
+		err := throttler.RegisterSuccess(context.Background(), "admin")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"go.uber.org/zap"
+)
+
+type UnfinishedOrdersGetter interface {
+	GetUnfinishedOrders(ctx context.Context, afterNumber string, limit int) ([]models.Order, error)
+}
+
+type OrdersEnqueuer interface {
+	Add(ctx context.Context, order models.Order) error
+}
+
+// RecoverOrders возвращает в очередь заказы, обработка которых не завершилась до остановки сервиса.
+// Повторное добавление заказа, который уже есть в очереди, ничего не меняет.
+func RecoverOrders(
+	ctx context.Context,
+	ordersGetter UnfinishedOrdersGetter,
+	queue OrdersEnqueuer,
+	logger *zap.Logger,
+	batchSize int,
+) (int, error) {
+	recovered := 0
+	afterNumber := ""
+
+	for {
+		orders, err := ordersGetter.GetUnfinishedOrders(ctx, afterNumber, batchSize)
+		if err != nil {
+			return recovered, err
+		}
+
+		for _, order := range orders {
+			err = queue.Add(ctx, order)
+			if err != nil {
+				return recovered, err
+			}
+			recovered++
+		}
+
+		if len(orders) > 0 {
+			logger.Debug("recovered batch of unfinished orders",
+				zap.Int("batch_size", len(orders)),
+				zap.Int("recovered", recovered),
+			)
+		}
+
+		if len(orders) < batchSize {
+			return recovered, nil
+		}
+
+		afterNumber = orders[len(orders)-1].OrderNumber
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestRecoverOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	logger := zap.NewExample()
+
+	o1 := models.Order{OrderNumber: "111"}
+	o2 := models.Order{OrderNumber: "222"}
+	o3 := models.Order{OrderNumber: "333"}
+
+	t.Run("orders recovered in batches", func(t *testing.T) {
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		gomock.InOrder(
+			ordersStorage.EXPECT().GetUnfinishedOrders(gomock.Any(), "", 2).Return([]models.Order{o1, o2}, nil),
+			ordersStorage.EXPECT().GetUnfinishedOrders(gomock.Any(), "222", 2).Return([]models.Order{o3}, nil),
+		)
+
+		queue := mocks.NewMockOrdersQueue(ctrl)
+		gomock.InOrder(
+			queue.EXPECT().Add(gomock.Any(), o1).Return(nil),
+			queue.EXPECT().Add(gomock.Any(), o2).Return(nil),
+			queue.EXPECT().Add(gomock.Any(), o3).Return(nil),
+		)
+
+		ordersService := NewOrdersService(ordersStorage, mocks.NewMockAccrualer(ctrl), logger)
+
+		recovered, err := RecoverOrders(context.Background(), ordersService, queue, logger, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, recovered)
+	})
+
+	t.Run("full last batch", func(t *testing.T) {
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		gomock.InOrder(
+			ordersStorage.EXPECT().GetUnfinishedOrders(gomock.Any(), "", 2).Return([]models.Order{o1, o2}, nil),
+			ordersStorage.EXPECT().GetUnfinishedOrders(gomock.Any(), "222", 2).Return([]models.Order{}, nil),
+		)
+
+		queue := mocks.NewMockOrdersQueue(ctrl)
+		queue.EXPECT().Add(gomock.Any(), gomock.Any()).Times(2).Return(nil)
+
+		recovered, err := RecoverOrders(context.Background(), ordersStorage, queue, logger, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, recovered)
+	})
+
+	t.Run("queue failed", func(t *testing.T) {
+		queueErr := errors.New("queue failed")
+
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().GetUnfinishedOrders(gomock.Any(), "", 2).Return([]models.Order{o1, o2}, nil)
+
+		queue := mocks.NewMockOrdersQueue(ctrl)
+		queue.EXPECT().Add(gomock.Any(), o1).Return(queueErr)
+
+		recovered, err := RecoverOrders(context.Background(), ordersStorage, queue, logger, 2)
+
+		assert.ErrorIs(t, err, queueErr)
+		assert.Equal(t, 0, recovered)
+	})
+}
@@ -25,6 +25,7 @@ type OrdersService struct {
 var (
 	ErrOrderAlreadyCreated              = errors.New("order already created")
 	ErrOrderAlreadyCreatedByAnotherUser = errors.New("order already created by another user")
+	ErrOrderNotFound                    = errors.New("order not found")
 )
 
 func (o *OrdersService) Add(ctx context.Context, orderNumber string, user models.User) (models.Order, error) {
@@ -54,9 +55,20 @@ func (o *OrdersService) UpdateAccrual(
 	ctx context.Context,
 	order models.Order,
 ) (models.Order, error) {
+	// заказ уже обработан по вебхуку, опрашивать accrual больше не нужно
+	if o.HasProcessedStatus(order) {
+		return order, nil
+	}
+
 	if order.Status == types.OrderStatusNew {
 		order.Status = types.OrderStatusProcessing
-		err := o.ordersStorage.UpdateStatus(ctx, order)
+		err := o.ordersStorage.UpdateStatus(ctx, order, types.OrderStatusSourcePoller)
+		if errors.Is(err, orders.ErrOrderAlreadyFinished) {
+			o.logger.Debug("order already finished",
+				zap.String("order_number", order.OrderNumber),
+			)
+			return order, nil
+		}
 		if err != nil {
 			o.logger.Error("failed to set processing status",
 				zap.String("order_number", order.OrderNumber),
@@ -92,27 +104,85 @@ func (o *OrdersService) UpdateAccrual(
 		}
 	}
 
-	if order.Accrual.IsZero() {
-		err := o.ordersStorage.UpdateStatus(ctx, order)
-		if err != nil {
-			o.logger.Error("failed to set processed status",
-				zap.String("order_number", order.OrderNumber),
-				zap.String("order_status", string(order.Status)),
-				zap.Error(err),
-			)
-			return order, err
+	return o.finish(ctx, order, types.OrderStatusSourcePoller)
+}
+
+// ApplyAccrualStatus применяет статус заказа, который accrual прислал через вебхук.
+// Повтор уже применённого статуса ничего не меняет, опрос accrual остаётся запасным путём.
+func (o *OrdersService) ApplyAccrualStatus(
+	ctx context.Context,
+	orderNumber string,
+	accrualStatus string,
+	accrual decimal.Decimal,
+) (models.Order, error) {
+	order, err := o.ordersStorage.GetByNumber(ctx, orderNumber)
+	if err != nil {
+		if errors.Is(err, orders.ErrOrderNotFound) {
+			return order, ErrOrderNotFound
 		}
-	} else {
-		err := o.ordersStorage.UpdateAccrual(ctx, order)
-		if err != nil {
-			o.logger.Error("failed to update accrual",
-				zap.String("order_number", order.OrderNumber),
-				zap.String("order_status", string(order.Status)),
-				zap.String("accrual", order.Accrual.String()),
-				zap.Error(err),
-			)
+		return order, err
+	}
+
+	if o.HasProcessedStatus(order) {
+		return order, nil
+	}
+
+	switch accrualStatus {
+	case statusRegistered, statusProcessing:
+		if order.Status != types.OrderStatusNew {
+			return order, nil
+		}
+
+		order.Status = types.OrderStatusProcessing
+		err := o.ordersStorage.UpdateStatus(ctx, order, types.OrderStatusSourceWebhook)
+		if err != nil && !errors.Is(err, orders.ErrOrderAlreadyFinished) {
 			return order, err
 		}
+		return order, nil
+	case statusInvalid:
+		order.Status = types.OrderStatusInvalid
+		order.Accrual = decimal.Zero
+	case statusProcessed:
+		if accrual.IsNegative() {
+			return order, ErrAccrualNegativeAmount
+		}
+		order.Status = types.OrderStatusProcessed
+		order.Accrual = accrual
+	default:
+		return order, ErrAccrualUnknownStatus
+	}
+
+	return o.finish(ctx, order, types.OrderStatusSourceWebhook)
+}
+
+// finish сохраняет итоговый статус заказа и начисляет баллы
+func (o *OrdersService) finish(
+	ctx context.Context,
+	order models.Order,
+	source types.OrderStatusSource,
+) (models.Order, error) {
+	var err error
+	if order.Accrual.IsZero() {
+		err = o.ordersStorage.UpdateStatus(ctx, order, source)
+	} else {
+		err = o.ordersStorage.UpdateAccrual(ctx, order, source)
+	}
+
+	if errors.Is(err, orders.ErrOrderAlreadyFinished) {
+		// вебхук и опрос могли прийти одновременно, баллы уже начислены одним из них
+		o.logger.Debug("order already finished",
+			zap.String("order_number", order.OrderNumber),
+		)
+		return order, nil
+	}
+	if err != nil {
+		o.logger.Error("failed to finish order",
+			zap.String("order_number", order.OrderNumber),
+			zap.String("order_status", string(order.Status)),
+			zap.String("accrual", order.Accrual.String()),
+			zap.Error(err),
+		)
+		return order, err
 	}
 
 	return order, nil
@@ -121,13 +191,76 @@ func (o *OrdersService) UpdateAccrual(
 func (o *OrdersService) GetUserOrders(
 	ctx context.Context,
 	user models.User,
-) ([]models.Order, error) {
-	orders, err := o.ordersStorage.GetUserOrders(ctx, user)
+	filter models.OrdersFilter,
+) (models.OrdersPage, error) {
+	pageSize := filter.Limit
+
+	// лишний заказ показывает, что есть следующая страница
+	if pageSize > 0 {
+		filter.Limit = pageSize + 1
+	}
+
+	orders, err := o.ordersStorage.GetUserOrders(ctx, user, filter)
+	if err != nil {
+		return models.OrdersPage{}, err
+	}
+
+	total, err := o.ordersStorage.CountUserOrders(ctx, user, filter)
+	if err != nil {
+		return models.OrdersPage{}, err
+	}
+
+	page := models.OrdersPage{
+		Orders: orders,
+		Total:  total,
+	}
+
+	if pageSize > 0 && len(orders) > pageSize {
+		page.Orders = orders[:pageSize]
+
+		last := page.Orders[pageSize-1]
+		page.Next = models.PageCursor{
+			At:  last.UploadedAt,
+			Key: last.OrderNumber,
+		}
+	}
+
+	return page, nil
+}
+
+// GetUserOrder возвращает заказ пользователя вместе с историей статусов.
+// Чужой заказ не отличается от несуществующего, чтобы не раскрывать чужие номера.
+func (o *OrdersService) GetUserOrder(
+	ctx context.Context,
+	user models.User,
+	orderNumber string,
+) (models.Order, []models.OrderStatusChange, error) {
+	order, err := o.ordersStorage.GetByNumber(ctx, orderNumber)
 	if err != nil {
-		return []models.Order{}, err
+		if errors.Is(err, orders.ErrOrderNotFound) {
+			return models.Order{}, nil, ErrOrderNotFound
+		}
+		return models.Order{}, nil, err
 	}
 
-	return orders, nil
+	if order.UserID != user.ID {
+		return models.Order{}, nil, ErrOrderNotFound
+	}
+
+	history, err := o.ordersStorage.GetStatusHistory(ctx, order.OrderNumber)
+	if err != nil {
+		return models.Order{}, nil, err
+	}
+
+	return order, history, nil
+}
+
+func (o *OrdersService) GetUnfinishedOrders(
+	ctx context.Context,
+	afterNumber string,
+	limit int,
+) ([]models.Order, error) {
+	return o.ordersStorage.GetUnfinishedOrders(ctx, afterNumber, limit)
 }
 
 func (o *OrdersService) HasProcessedStatus(order models.Order) bool {
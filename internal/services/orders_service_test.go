@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/orders"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestOrdersServiceAccrualUpdates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	logger := zap.NewExample()
+
+	newOrder := models.Order{
+		OrderNumber: "12345678903",
+		UserID:      1,
+		Accrual:     decimal.Zero,
+		Status:      types.OrderStatusNew,
+	}
+
+	t.Run("polling skips order finished by webhook", func(t *testing.T) {
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), types.OrderStatusSourcePoller).Return(orders.ErrOrderAlreadyFinished)
+
+		// accrual не опрашивается
+		ordersService := NewOrdersService(ordersStorage, mocks.NewMockAccrualer(ctrl), logger)
+
+		_, err := ordersService.UpdateAccrual(context.Background(), newOrder)
+		assert.NoError(t, err)
+	})
+
+	t.Run("job for order finished by webhook", func(t *testing.T) {
+		processedOrder := newOrder
+		processedOrder.Status = types.OrderStatusProcessed
+
+		ordersService := NewOrdersService(mocks.NewMockOrdersStorage(ctrl), mocks.NewMockAccrualer(ctrl), logger)
+
+		_, err := ordersService.UpdateAccrual(context.Background(), processedOrder)
+		assert.NoError(t, err)
+	})
+
+	t.Run("polling races with webhook", func(t *testing.T) {
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), types.OrderStatusSourcePoller).Return(nil)
+		ordersStorage.EXPECT().UpdateAccrual(gomock.Any(), gomock.Any(), types.OrderStatusSourcePoller).Return(orders.ErrOrderAlreadyFinished)
+
+		accrualer := mocks.NewMockAccrualer(ctrl)
+		accrualer.EXPECT().GetAccrual(gomock.Any(), newOrder.OrderNumber).Return(decimal.NewFromInt(500), nil)
+
+		ordersService := NewOrdersService(ordersStorage, accrualer, logger)
+
+		order, err := ordersService.UpdateAccrual(context.Background(), newOrder)
+		require.NoError(t, err)
+		assert.Equal(t, types.OrderStatusProcessed, order.Status)
+	})
+
+	t.Run("webhook processes order", func(t *testing.T) {
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().GetByNumber(gomock.Any(), newOrder.OrderNumber).Return(newOrder, nil)
+		ordersStorage.EXPECT().
+			UpdateAccrual(gomock.Any(), gomock.Cond(func(order models.Order) bool {
+				return order.Status == types.OrderStatusProcessed && order.Accrual.Equal(decimal.RequireFromString("729.98"))
+			}), types.OrderStatusSourceWebhook).
+			Return(nil)
+
+		ordersService := NewOrdersService(ordersStorage, mocks.NewMockAccrualer(ctrl), logger)
+
+		order, err := ordersService.ApplyAccrualStatus(context.Background(), newOrder.OrderNumber, "PROCESSED", decimal.RequireFromString("729.98"))
+		require.NoError(t, err)
+		assert.Equal(t, types.OrderStatusProcessed, order.Status)
+	})
+
+	t.Run("repeated webhook", func(t *testing.T) {
+		processedOrder := newOrder
+		processedOrder.Status = types.OrderStatusProcessed
+		processedOrder.Accrual = decimal.NewFromInt(500)
+
+		// второго начисления нет
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().GetByNumber(gomock.Any(), newOrder.OrderNumber).Return(processedOrder, nil)
+
+		ordersService := NewOrdersService(ordersStorage, mocks.NewMockAccrualer(ctrl), logger)
+
+		order, err := ordersService.ApplyAccrualStatus(context.Background(), newOrder.OrderNumber, "PROCESSED", decimal.NewFromInt(500))
+		require.NoError(t, err)
+		assert.Equal(t, "500", order.Accrual.String())
+	})
+
+	t.Run("webhook with intermediate status", func(t *testing.T) {
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().GetByNumber(gomock.Any(), newOrder.OrderNumber).Return(newOrder, nil)
+		ordersStorage.EXPECT().
+			UpdateStatus(gomock.Any(), gomock.Cond(func(order models.Order) bool {
+				return order.Status == types.OrderStatusProcessing
+			}), types.OrderStatusSourceWebhook).
+			Return(nil)
+
+		ordersService := NewOrdersService(ordersStorage, mocks.NewMockAccrualer(ctrl), logger)
+
+		order, err := ordersService.ApplyAccrualStatus(context.Background(), newOrder.OrderNumber, "REGISTERED", decimal.Zero)
+		require.NoError(t, err)
+		assert.Equal(t, types.OrderStatusProcessing, order.Status)
+	})
+
+	t.Run("webhook for invalid order", func(t *testing.T) {
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().GetByNumber(gomock.Any(), newOrder.OrderNumber).Return(newOrder, nil)
+		ordersStorage.EXPECT().
+			UpdateStatus(gomock.Any(), gomock.Cond(func(order models.Order) bool {
+				return order.Status == types.OrderStatusInvalid
+			}), types.OrderStatusSourceWebhook).
+			Return(nil)
+
+		ordersService := NewOrdersService(ordersStorage, mocks.NewMockAccrualer(ctrl), logger)
+
+		_, err := ordersService.ApplyAccrualStatus(context.Background(), newOrder.OrderNumber, "INVALID", decimal.Zero)
+		assert.NoError(t, err)
+	})
+
+	t.Run("webhook for unknown order", func(t *testing.T) {
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().GetByNumber(gomock.Any(), "79927398713").Return(models.Order{}, orders.ErrOrderNotFound)
+
+		ordersService := NewOrdersService(ordersStorage, mocks.NewMockAccrualer(ctrl), logger)
+
+		_, err := ordersService.ApplyAccrualStatus(context.Background(), "79927398713", "PROCESSED", decimal.NewFromInt(500))
+		assert.ErrorIs(t, err, ErrOrderNotFound)
+	})
+}
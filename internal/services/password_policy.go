@@ -0,0 +1,63 @@
+package services
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// bcrypt учитывает только первые 72 байта пароля
+const maxPasswordBytes = 72
+
+type PasswordPolicy struct {
+	MinLength int
+
+	// MinCharClasses сколько разных групп символов должно быть в пароле:
+	// строчные и заглавные буквы, цифры, остальные символы
+	MinCharClasses int
+}
+
+func (p PasswordPolicy) Check(password string) bool {
+	if len(password) > maxPasswordBytes || !utf8.ValidString(password) {
+		return false
+	}
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return false
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsControl(r):
+			return false
+		default:
+			hasSymbol = true
+		}
+	}
+
+	classes := 0
+	for _, has := range []bool{hasLower, hasUpper, hasDigit, hasSymbol} {
+		if has {
+			classes++
+		}
+	}
+
+	return classes >= p.MinCharClasses
+}
+
+func (p PasswordPolicy) Description() string {
+	description := fmt.Sprintf("must be from %d characters up to %d bytes", p.MinLength, maxPasswordBytes)
+	if p.MinCharClasses > 1 {
+		description += fmt.Sprintf(
+			" and contain at least %d of: lowercase letters, uppercase letters, digits, symbols",
+			p.MinCharClasses,
+		)
+	}
+	return description
+}
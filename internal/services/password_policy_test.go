@@ -0,0 +1,57 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:      8,
+		MinCharClasses: 2,
+	}
+
+	tests := []struct {
+		password string
+		valid    bool
+	}{
+		{password: "short1", valid: false},
+		{password: "onlyletters", valid: false},
+		{password: "12345678", valid: false},
+		{password: "letters123", valid: true},
+		{password: "pass word!", valid: true},
+		{password: "Пароль-на-русском", valid: true},
+		{password: "tab\tinside1", valid: false},
+		{password: strings.Repeat("a1", 36), valid: true},
+		{password: strings.Repeat("a1", 36) + "a", valid: false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.valid, policy.Check(test.password), test.password)
+	}
+}
+
+func TestDefaultPasswordPolicy(t *testing.T) {
+	// по умолчанию проходят все пароли, которые принимались до появления политики, и пароли с символами
+	policy := PasswordPolicy{
+		MinLength:      6,
+		MinCharClasses: 1,
+	}
+
+	tests := []struct {
+		password string
+		valid    bool
+	}{
+		{password: "abcde", valid: false},
+		{password: "abcdef", valid: true},
+		{password: "abcdefgh", valid: true},
+		{password: "secret", valid: true},
+		{password: "s3cret-pass!", valid: true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.valid, policy.Check(test.password), test.password)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/jobs"
 	"go.uber.org/zap"
 )
 
@@ -28,30 +30,35 @@ func (e *ErrWorkerRetry) Error() string {
 	return fmt.Sprintf("worker should retry jobs after %d", e.RetryAfter)
 }
 
-type MemoryOrdersQueue struct {
-	processor OrderJobProcessor
-	logger    *zap.Logger
+type PostgresOrdersQueue struct {
+	processor   OrderJobProcessor
+	jobsStorage jobs.Storage
+	logger      *zap.Logger
 
-	jobsQueue  chan models.Order
-	jobTimeout time.Duration
-	jobsDelay  time.Duration
+	jobTimeout   time.Duration
+	jobLease     time.Duration
+	retryPolicy  RetryPolicy
+	pollInterval time.Duration
+
+	stopped  chan struct{}
+	stopOnce sync.Once
 }
 
-func (q *MemoryOrdersQueue) Add(ctx context.Context, order models.Order) error {
-	select {
-	case <-ctx.Done():
-		return errors.New("failed to add to order queue")
-	case q.jobsQueue <- order:
-		return nil
-	}
+func (q *PostgresOrdersQueue) Add(ctx context.Context, order models.Order) error {
+	return q.jobsStorage.Push(ctx, models.OrderJob{
+		Order:   order,
+		JobName: q.processor.GetName(),
+	})
 }
 
 // завершать после остановки хэндлеров
-func (q *MemoryOrdersQueue) Stop() {
-	close(q.jobsQueue)
+func (q *PostgresOrdersQueue) Stop() {
+	q.stopOnce.Do(func() {
+		close(q.stopped)
+	})
 }
 
-func (q *MemoryOrdersQueue) RunWorker(ctx context.Context) {
+func (q *PostgresOrdersQueue) RunWorker(ctx context.Context) {
 	q.logger.Info("running queue worker...",
 		zap.String("job_name", q.processor.GetName()),
 	)
@@ -62,97 +69,167 @@ func (q *MemoryOrdersQueue) RunWorker(ctx context.Context) {
 			return
 		}
 
-		select {
-		case <-ctx.Done():
-			return
-		case order := <-q.jobsQueue:
-			jobCtx, cancel := context.WithTimeout(ctx, q.jobTimeout)
-			defer cancel()
-
-			order, err := q.processor.Process(jobCtx, order)
-			if err != nil {
-				var e *ErrWorkerRetry
-				if errors.As(err, &e) {
-					q.logger.Info("pausing worker...",
-						zap.String("order_number", order.OrderNumber),
-						zap.String("job_name", q.processor.GetName()),
-						zap.Int("retry_delay", e.RetryAfter),
-						zap.Error(e),
-					)
-
-					workerDelay := time.Second * time.Duration(e.RetryAfter)
-
-					err := q.retry(ctx, order, workerDelay)
-					if err != nil {
-						q.logger.Error("failed to retry job after worker was paused",
-							zap.String("order_number", order.OrderNumber),
-							zap.String("job_name", q.processor.GetName()),
-							zap.Error(err),
-						)
-					}
-				} else if errors.Is(err, ErrJobRetry) {
-					q.logger.Info("retrying order job",
-						zap.String("order_number", order.OrderNumber),
-						zap.String("job_name", q.processor.GetName()),
-					)
-
-					go func() {
-						retryCtx, cancel := context.WithTimeout(ctx, q.jobTimeout)
-						defer cancel()
-
-						err := q.retry(retryCtx, order, q.jobsDelay)
-						if err != nil {
-							q.logger.Error("failed to retry job",
-								zap.String("order_number", order.OrderNumber),
-								zap.String("job_name", q.processor.GetName()),
-								zap.Error(err),
-							)
-						}
-					}()
-				} else {
-					q.logger.Error("order job failed",
-						zap.String("order_number", order.OrderNumber),
-						zap.String("job_name", q.processor.GetName()),
-					)
-				}
-			} else {
-				q.logger.Debug("order job finished",
-					zap.String("order_number", order.OrderNumber),
+		job, err := q.jobsStorage.Claim(ctx, q.processor.GetName(), q.jobLease)
+		if err != nil {
+			if !errors.Is(err, jobs.ErrNoJobs) {
+				q.logger.Error("failed to claim order job",
 					zap.String("job_name", q.processor.GetName()),
+					zap.Error(err),
 				)
 			}
+
+			if !q.wait(ctx, q.pollInterval) {
+				return
+			}
+			continue
+		}
+
+		workerDelay := q.processJob(ctx, job)
+		if workerDelay > 0 && !q.wait(ctx, workerDelay) {
+			return
+		}
+	}
+}
+
+// processJob возвращает время, на которое нужно приостановить воркер
+func (q *PostgresOrdersQueue) processJob(ctx context.Context, job models.OrderJob) time.Duration {
+	jobCtx, cancel := context.WithTimeout(ctx, q.jobTimeout)
+	defer cancel()
+
+	_, err := q.processor.Process(jobCtx, job.Order)
+	if err == nil {
+		q.logger.Debug("order job finished",
+			zap.String("order_number", job.Order.OrderNumber),
+			zap.String("job_name", job.JobName),
+		)
+
+		err = q.jobsStorage.Complete(ctx, job)
+		if err != nil {
+			q.logger.Error("failed to complete order job",
+				zap.String("order_number", job.Order.OrderNumber),
+				zap.String("job_name", job.JobName),
+				zap.Error(err),
+			)
+		}
+		return 0
+	}
+
+	var e *ErrWorkerRetry
+	if errors.As(err, &e) {
+		workerDelay := time.Second * time.Duration(e.RetryAfter)
+
+		q.logger.Info("pausing worker...",
+			zap.String("order_number", job.Order.OrderNumber),
+			zap.String("job_name", job.JobName),
+			zap.Int("retry_delay", e.RetryAfter),
+			zap.Error(e),
+		)
+
+		// лимит accrual не проблема конкретного заказа, попытку не засчитываем
+		err = q.jobsStorage.Postpone(ctx, job, workerDelay)
+		if err != nil {
+			q.logger.Error("failed to postpone order job",
+				zap.String("order_number", job.Order.OrderNumber),
+				zap.String("job_name", job.JobName),
+				zap.Error(err),
+			)
+		}
+		return workerDelay
+	}
+
+	if errors.Is(err, ErrJobRetry) {
+		job.LastError = err.Error()
+
+		if q.retryPolicy.IsExhausted(job.Attempts) {
+			q.logger.Error("order job exhausted retries, moving to dead letter",
+				zap.String("order_number", job.Order.OrderNumber),
+				zap.String("job_name", job.JobName),
+				zap.Int("attempts", job.Attempts),
+				zap.Error(err),
+			)
+
+			q.fail(ctx, job)
+			return 0
+		}
+
+		retryDelay := q.retryPolicy.Delay(job.Attempts)
+
+		q.logger.Info("retrying order job",
+			zap.String("order_number", job.Order.OrderNumber),
+			zap.String("job_name", job.JobName),
+			zap.Int("attempts", job.Attempts),
+			zap.Duration("retry_delay", retryDelay),
+			zap.Error(err),
+		)
+
+		err = q.jobsStorage.Reschedule(ctx, job, retryDelay)
+		if err != nil {
+			// задача всё равно вернётся в очередь после истечения lease
+			q.logger.Error("failed to reschedule order job",
+				zap.String("order_number", job.Order.OrderNumber),
+				zap.String("job_name", job.JobName),
+				zap.Error(err),
+			)
 		}
+		return 0
 	}
+
+	job.LastError = err.Error()
+
+	q.logger.Error("order job failed, moving to dead letter",
+		zap.String("order_number", job.Order.OrderNumber),
+		zap.String("job_name", job.JobName),
+		zap.Int("attempts", job.Attempts),
+		zap.Error(err),
+	)
+
+	q.fail(ctx, job)
+	return 0
 }
 
-func (q *MemoryOrdersQueue) retry(
-	ctx context.Context,
-	order models.Order,
-	delay time.Duration,
-) error {
+func (q *PostgresOrdersQueue) fail(ctx context.Context, job models.OrderJob) {
+	err := q.jobsStorage.Fail(ctx, job)
+	if err != nil {
+		q.logger.Error("failed to move order job to dead letter",
+			zap.String("order_number", job.Order.OrderNumber),
+			zap.String("job_name", job.JobName),
+			zap.Error(err),
+		)
+	}
+}
+
+// wait возвращает false, если воркер нужно завершить
+func (q *PostgresOrdersQueue) wait(ctx context.Context, delay time.Duration) bool {
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return false
+	case <-q.stopped:
+		return false
 	case <-time.After(delay):
-		return q.Add(ctx, order)
+		return true
 	}
 }
 
 func NewOrdersQueue(
 	ordersProcessor OrderJobProcessor,
+	jobsStorage jobs.Storage,
 	logger *zap.Logger,
-	jobsDelay time.Duration,
-) *MemoryOrdersQueue {
+	retryPolicy RetryPolicy,
+	pollInterval time.Duration,
+) *PostgresOrdersQueue {
 	jobTimeout := 10 * time.Second
-	jobsQueue := make(chan models.Order, 100)
 
-	queue := MemoryOrdersQueue{
-		processor: ordersProcessor,
-		logger:    logger,
+	queue := PostgresOrdersQueue{
+		processor:   ordersProcessor,
+		jobsStorage: jobsStorage,
+		logger:      logger,
+
+		jobTimeout:   jobTimeout,
+		jobLease:     3 * jobTimeout,
+		retryPolicy:  retryPolicy,
+		pollInterval: pollInterval,
 
-		jobsQueue:  jobsQueue,
-		jobTimeout: jobTimeout,
-		jobsDelay:  jobsDelay,
+		stopped: make(chan struct{}),
 	}
 
 	return &queue
@@ -7,6 +7,7 @@ import (
 
 	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/jobs"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
@@ -24,47 +25,90 @@ func TestQueue(t *testing.T) {
 		OrderNumber: "222",
 	}
 
-	jobsDelay := time.Duration(0)
+	retryPolicy := RetryPolicy{}
+	pollInterval := 5 * time.Millisecond
+
+	newJob := func(jobName string, order models.Order) models.OrderJob {
+		return models.OrderJob{
+			Order:    order,
+			JobName:  jobName,
+			Attempts: 1,
+		}
+	}
+
+	t.Run("jobs added", func(t *testing.T) {
+		testProcessor := mocks.NewMockOrderJobProcessor(ctrl)
+		testProcessor.EXPECT().GetName().AnyTimes().Return("jobs added")
+
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		jobsStorage.EXPECT().Push(gomock.Any(), models.OrderJob{
+			Order:   o1,
+			JobName: "jobs added",
+		}).Return(nil)
+
+		queue := NewOrdersQueue(testProcessor, jobsStorage, logger, retryPolicy, pollInterval)
+		defer queue.Stop()
+
+		err := queue.Add(context.Background(), o1)
+		assert.NoError(t, err, "order 1 should be added")
+	})
 
 	t.Run("jobs completed", func(t *testing.T) {
+		j1 := newJob("jobs completed", o1)
+		j2 := newJob("jobs completed", o2)
+
 		testProcessor := mocks.NewMockOrderJobProcessor(ctrl)
 		testProcessor.EXPECT().GetName().AnyTimes().Return("jobs completed")
 		testProcessor.EXPECT().Process(gomock.Any(), o1).Return(o1, nil)
 		testProcessor.EXPECT().Process(gomock.Any(), o2).Return(o2, nil)
 
-		queue := NewOrdersQueue(testProcessor, logger, jobsDelay)
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		gomock.InOrder(
+			jobsStorage.EXPECT().Claim(gomock.Any(), "jobs completed", gomock.Any()).Return(j1, nil),
+			jobsStorage.EXPECT().Complete(gomock.Any(), j1).Return(nil),
+			jobsStorage.EXPECT().Claim(gomock.Any(), "jobs completed", gomock.Any()).Return(j2, nil),
+			jobsStorage.EXPECT().Complete(gomock.Any(), j2).Return(nil),
+		)
+		jobsStorage.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(models.OrderJob{}, jobs.ErrNoJobs)
+
+		queue := NewOrdersQueue(testProcessor, jobsStorage, logger, retryPolicy, pollInterval)
 		defer queue.Stop()
 
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 		defer cancel()
 
-		err := queue.Add(ctx, o1)
-		assert.NoError(t, err, "order 1 should be added")
-		err = queue.Add(ctx, o2)
-		assert.NoError(t, err, "order 2 should be added")
-
 		queue.RunWorker(ctx)
 	})
 
 	t.Run("job retried", func(t *testing.T) {
+		j1 := newJob("job retried", o1)
+
 		testProcessor := mocks.NewMockOrderJobProcessor(ctrl)
 		testProcessor.EXPECT().GetName().AnyTimes().Return("job retried")
 		testProcessor.EXPECT().Process(gomock.Any(), o1).Return(o1, ErrJobRetry)
 		testProcessor.EXPECT().Process(gomock.Any(), o1).Return(o1, nil)
 
-		queue := NewOrdersQueue(testProcessor, logger, jobsDelay)
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		gomock.InOrder(
+			jobsStorage.EXPECT().Claim(gomock.Any(), "job retried", gomock.Any()).Return(j1, nil),
+			jobsStorage.EXPECT().Reschedule(gomock.Any(), gomock.Any(), time.Duration(0)).Return(nil),
+			jobsStorage.EXPECT().Claim(gomock.Any(), "job retried", gomock.Any()).Return(j1, nil),
+			jobsStorage.EXPECT().Complete(gomock.Any(), j1).Return(nil),
+		)
+		jobsStorage.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(models.OrderJob{}, jobs.ErrNoJobs)
+
+		queue := NewOrdersQueue(testProcessor, jobsStorage, logger, retryPolicy, pollInterval)
 		defer queue.Stop()
 
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 		defer cancel()
 
-		err := queue.Add(ctx, o1)
-		assert.NoError(t, err, "order 1 should be added")
-
 		queue.RunWorker(ctx)
 	})
 
 	t.Run("worker retried", func(t *testing.T) {
+		j1 := newJob("worker retried", o1)
+
 		testProcessor := mocks.NewMockOrderJobProcessor(ctrl)
 		testProcessor.EXPECT().GetName().AnyTimes().Return("worker retried")
 		testProcessor.EXPECT().Process(gomock.Any(), o1).Return(o1, &ErrWorkerRetry{
@@ -72,37 +116,149 @@ func TestQueue(t *testing.T) {
 		})
 		testProcessor.EXPECT().Process(gomock.Any(), o1).Return(o1, nil)
 
-		queue := NewOrdersQueue(testProcessor, logger, jobsDelay)
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		gomock.InOrder(
+			jobsStorage.EXPECT().Claim(gomock.Any(), "worker retried", gomock.Any()).Return(j1, nil),
+			jobsStorage.EXPECT().Postpone(gomock.Any(), j1, time.Duration(0)).Return(nil),
+			jobsStorage.EXPECT().Claim(gomock.Any(), "worker retried", gomock.Any()).Return(j1, nil),
+			jobsStorage.EXPECT().Complete(gomock.Any(), j1).Return(nil),
+		)
+		jobsStorage.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(models.OrderJob{}, jobs.ErrNoJobs)
+
+		queue := NewOrdersQueue(testProcessor, jobsStorage, logger, retryPolicy, pollInterval)
 		defer queue.Stop()
 
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 		defer cancel()
 
-		err := queue.Add(ctx, o1)
-		assert.NoError(t, err, "order 1 should be added")
-
 		queue.RunWorker(ctx)
 	})
 
 	t.Run("worker paused", func(t *testing.T) {
+		j1 := newJob("worker paused", o1)
+
 		testProcessor := mocks.NewMockOrderJobProcessor(ctrl)
 		testProcessor.EXPECT().GetName().AnyTimes().Return("worker paused")
 		testProcessor.EXPECT().Process(gomock.Any(), o1).Return(o1, &ErrWorkerRetry{
 			RetryAfter: 1,
 		})
 
-		queue := NewOrdersQueue(testProcessor, logger, jobsDelay)
+		// второй заказ не должен быть взят в работу, пока воркер на паузе
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		jobsStorage.EXPECT().Claim(gomock.Any(), "worker paused", gomock.Any()).Return(j1, nil)
+		jobsStorage.EXPECT().Postpone(gomock.Any(), j1, time.Second).Return(nil)
+
+		queue := NewOrdersQueue(testProcessor, jobsStorage, logger, retryPolicy, pollInterval)
 		defer queue.Stop()
 
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 		defer cancel()
 
-		err := queue.Add(ctx, o1)
-		assert.NoError(t, err, "order 1 should be added")
+		queue.RunWorker(ctx)
+	})
+
+	t.Run("job retried with backoff", func(t *testing.T) {
+		j1 := newJob("job retried with backoff", o1)
+		j1.Attempts = 2
+
+		testProcessor := mocks.NewMockOrderJobProcessor(ctrl)
+		testProcessor.EXPECT().GetName().AnyTimes().Return("job retried with backoff")
+		testProcessor.EXPECT().Process(gomock.Any(), o1).Return(o1, ErrJobRetry)
+
+		expectedJob := j1
+		expectedJob.LastError = ErrJobRetry.Error()
+
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		jobsStorage.EXPECT().Claim(gomock.Any(), "job retried with backoff", gomock.Any()).Return(j1, nil)
+		jobsStorage.EXPECT().
+			Reschedule(gomock.Any(), expectedJob, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, job models.OrderJob, delay time.Duration) error {
+				assert.GreaterOrEqual(t, delay, time.Minute)
+				assert.LessOrEqual(t, delay, 2*time.Minute)
+				return nil
+			})
+		jobsStorage.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(models.OrderJob{}, jobs.ErrNoJobs)
+
+		policy := RetryPolicy{
+			BaseDelay:   time.Minute,
+			MaxDelay:    time.Hour,
+			MaxAttempts: 3,
+		}
+
+		queue := NewOrdersQueue(testProcessor, jobsStorage, logger, policy, pollInterval)
+		defer queue.Stop()
 
-		err = queue.Add(ctx, o2)
-		assert.NoError(t, err, "order 2 should be added")
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		queue.RunWorker(ctx)
+	})
+
+	t.Run("exhausted job moved to dead letter", func(t *testing.T) {
+		j1 := newJob("exhausted job moved to dead letter", o1)
+		j1.Attempts = 3
+
+		testProcessor := mocks.NewMockOrderJobProcessor(ctrl)
+		testProcessor.EXPECT().GetName().AnyTimes().Return("exhausted job moved to dead letter")
+		testProcessor.EXPECT().Process(gomock.Any(), o1).Return(o1, ErrJobRetry)
+
+		expectedJob := j1
+		expectedJob.LastError = ErrJobRetry.Error()
+
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		jobsStorage.EXPECT().Claim(gomock.Any(), "exhausted job moved to dead letter", gomock.Any()).Return(j1, nil)
+		jobsStorage.EXPECT().Fail(gomock.Any(), expectedJob).Return(nil)
+		jobsStorage.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(models.OrderJob{}, jobs.ErrNoJobs)
+
+		policy := RetryPolicy{
+			BaseDelay:   time.Minute,
+			MaxDelay:    time.Hour,
+			MaxAttempts: 3,
+		}
+
+		queue := NewOrdersQueue(testProcessor, jobsStorage, logger, policy, pollInterval)
+		defer queue.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		queue.RunWorker(ctx)
+	})
+
+	t.Run("failed job moved to dead letter", func(t *testing.T) {
+		j1 := newJob("failed job moved to dead letter", o1)
+
+		testProcessor := mocks.NewMockOrderJobProcessor(ctrl)
+		testProcessor.EXPECT().GetName().AnyTimes().Return("failed job moved to dead letter")
+		testProcessor.EXPECT().Process(gomock.Any(), o1).Return(o1, ErrAccrualUnexpectedError)
+
+		expectedJob := j1
+		expectedJob.LastError = ErrAccrualUnexpectedError.Error()
+
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		jobsStorage.EXPECT().Claim(gomock.Any(), "failed job moved to dead letter", gomock.Any()).Return(j1, nil)
+		jobsStorage.EXPECT().Fail(gomock.Any(), expectedJob).Return(nil)
+		jobsStorage.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(models.OrderJob{}, jobs.ErrNoJobs)
+
+		queue := NewOrdersQueue(testProcessor, jobsStorage, logger, retryPolicy, pollInterval)
+		defer queue.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
 
 		queue.RunWorker(ctx)
 	})
+
+	t.Run("worker stopped", func(t *testing.T) {
+		testProcessor := mocks.NewMockOrderJobProcessor(ctrl)
+		testProcessor.EXPECT().GetName().AnyTimes().Return("worker stopped")
+
+		jobsStorage := mocks.NewMockJobsStorage(ctrl)
+		jobsStorage.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(models.OrderJob{}, jobs.ErrNoJobs)
+
+		queue := NewOrdersQueue(testProcessor, jobsStorage, logger, retryPolicy, pollInterval)
+		queue.Stop()
+
+		queue.RunWorker(context.Background())
+	})
 }
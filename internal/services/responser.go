@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/responses"
 	"github.com/go-playground/validator/v10"
@@ -33,6 +35,21 @@ func (r *Responser) WriteUnauthorizedError(ctx context.Context, res http.Respons
 	r.writeError(ctx, res, http.StatusUnauthorized, "unauthorized")
 }
 
+func (r *Responser) WriteForbiddenError(ctx context.Context, res http.ResponseWriter) {
+	r.writeError(ctx, res, http.StatusForbidden, "forbidden")
+}
+
+// WriteTooManyRequestsError округляет retryAfter вверх до секунд, иначе клиент повторит слишком рано
+func (r *Responser) WriteTooManyRequestsError(ctx context.Context, res http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int64((retryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	res.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+
+	r.writeError(ctx, res, http.StatusTooManyRequests, "too many requests")
+}
+
 func (r *Responser) WriteNotFoundError(ctx context.Context, res http.ResponseWriter) {
 	r.writeError(ctx, res, http.StatusNotFound, "not found")
 }
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+const maxRetryDelay = 24 * time.Hour
+
+// RetryPolicy экспоненциальная задержка между попытками со случайным разбросом,
+// чтобы задачи, упавшие одновременно, не повторялись одной пачкой
+type RetryPolicy struct {
+	BaseDelay time.Duration
+
+	// 0 - без ограничения
+	MaxDelay time.Duration
+
+	// 0 - без ограничения
+	MaxAttempts int
+}
+
+// Delay возвращает задержку перед следующей попыткой после attempt неудачных
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	// половина задержки фиксирована, вторая половина случайна
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+func (p RetryPolicy) IsExhausted(attempts int) bool {
+	return p.MaxAttempts > 0 && attempts >= p.MaxAttempts
+}
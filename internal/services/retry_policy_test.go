@@ -0,0 +1,44 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay:   time.Second,
+		MaxDelay:    10 * time.Second,
+		MaxAttempts: 5,
+	}
+
+	tests := []struct {
+		attempt  int
+		minDelay time.Duration
+		maxDelay time.Duration
+	}{
+		{attempt: 1, minDelay: 500 * time.Millisecond, maxDelay: time.Second},
+		{attempt: 2, minDelay: time.Second, maxDelay: 2 * time.Second},
+		{attempt: 3, minDelay: 2 * time.Second, maxDelay: 4 * time.Second},
+		{attempt: 4, minDelay: 4 * time.Second, maxDelay: 8 * time.Second},
+		{attempt: 5, minDelay: 5 * time.Second, maxDelay: 10 * time.Second},
+		{attempt: 100, minDelay: 5 * time.Second, maxDelay: 10 * time.Second},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 20; i++ {
+			delay := policy.Delay(test.attempt)
+
+			assert.GreaterOrEqual(t, delay, test.minDelay, "attempt %d", test.attempt)
+			assert.LessOrEqual(t, delay, test.maxDelay, "attempt %d", test.attempt)
+		}
+	}
+
+	assert.False(t, policy.IsExhausted(4))
+	assert.True(t, policy.IsExhausted(5))
+
+	assert.False(t, RetryPolicy{}.IsExhausted(1000), "zero max attempts means unlimited")
+	assert.Equal(t, time.Duration(0), RetryPolicy{}.Delay(3))
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+)
+
+// WriteStatementCSV пишет выписку в CSV: строка с итогами периода, пустая строка и операции
+func WriteStatementCSV(w io.Writer, statement models.Statement) error {
+	csvWriter := csv.NewWriter(w)
+
+	records := [][]string{
+		{"login", "from", "to", "opening_balance", "credits", "debits", "closing_balance", "orders"},
+		{
+			statement.Login,
+			statement.From.Format(time.RFC3339),
+			statement.To.Format(time.RFC3339),
+			statement.OpeningBalance.StringFixed(2),
+			statement.Credits.StringFixed(2),
+			statement.Debits.StringFixed(2),
+			statement.ClosingBalance.StringFixed(2),
+			strconv.Itoa(statement.OrdersCount),
+		},
+		{},
+		{"processed_at", "type", "order", "amount", "balance"},
+	}
+
+	for _, entry := range statement.Entries {
+		records = append(records, []string{
+			entry.ProcessedAt.Format(time.RFC3339),
+			string(entry.Type),
+			entry.OrderNumber,
+			entry.Amount.StringFixed(2),
+			entry.Balance.StringFixed(2),
+		})
+	}
+
+	err := csvWriter.WriteAll(records)
+	if err != nil {
+		return err
+	}
+
+	return csvWriter.Error()
+}
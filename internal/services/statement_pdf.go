@@ -0,0 +1,141 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+)
+
+const (
+	// A4 в пунктах
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+
+	pdfMargin       = 50
+	pdfFontSize     = 9
+	pdfLeading      = 12
+	pdfLinesPerPage = 60
+)
+
+// WriteStatementPDF пишет выписку в PDF.
+// Документ собирается вручную: моноширинный стандартный шрифт Courier, только ASCII-текст, без сжатия.
+func WriteStatementPDF(w io.Writer, statement models.Statement) error {
+	lines := statementLines(statement)
+
+	pages := [][]string{}
+	for len(lines) > pdfLinesPerPage {
+		pages = append(pages, lines[:pdfLinesPerPage])
+		lines = lines[pdfLinesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	var buf bytes.Buffer
+	offsets := []int{}
+
+	writeObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// 1 - каталог, 2 - дерево страниц, 3 - шрифт, дальше по паре объектов на страницу
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 4+2*i))
+	}
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, pageLines := range pages {
+		content := pdfPageContent(pageLines, fmt.Sprintf("Page %d of %d", i+1, len(pages)))
+
+		writeObject(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 5+2*i,
+		))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xrefOffset)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func statementLines(statement models.Statement) []string {
+	lines := []string{
+		"Points statement",
+		"",
+		fmt.Sprintf("Login:           %s", statement.Login),
+		fmt.Sprintf("Period:          %s - %s", statement.From.Format(time.RFC3339), statement.To.Format(time.RFC3339)),
+		fmt.Sprintf("Opening balance: %s", statement.OpeningBalance.StringFixed(2)),
+		fmt.Sprintf("Credits:         %s", statement.Credits.StringFixed(2)),
+		fmt.Sprintf("Debits:          %s", statement.Debits.StringFixed(2)),
+		fmt.Sprintf("Closing balance: %s", statement.ClosingBalance.StringFixed(2)),
+		fmt.Sprintf("Orders uploaded: %d", statement.OrdersCount),
+		"",
+		fmt.Sprintf("%-20s %-10s %-20s %14s %14s", "Date", "Type", "Order", "Amount", "Balance"),
+		strings.Repeat("-", 82),
+	}
+
+	for _, entry := range statement.Entries {
+		lines = append(lines, fmt.Sprintf("%-20s %-10s %-20s %14s %14s",
+			entry.ProcessedAt.Format(time.RFC3339),
+			entry.Type,
+			entry.OrderNumber,
+			entry.Amount.StringFixed(2),
+			entry.Balance.StringFixed(2),
+		))
+	}
+
+	if len(statement.Entries) == 0 {
+		lines = append(lines, "No operations in this period")
+	}
+
+	return lines
+}
+
+func pdfPageContent(lines []string, footer string) string {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin)
+	for _, line := range lines {
+		fmt.Fprintf(&content, "(%s) Tj T*\n", pdfEscape(line))
+	}
+	content.WriteString("ET\n")
+
+	fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d %d Td\n(%s) Tj\nET", pdfFontSize, pdfMargin, pdfMargin/2, pdfEscape(footer))
+
+	return content.String()
+}
+
+// pdfEscape экранирует спецсимволы строки PDF и заменяет всё, что не ASCII
+func pdfEscape(s string) string {
+	var escaped strings.Builder
+
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			escaped.WriteRune('\\')
+			escaped.WriteRune(r)
+		case r < 32 || r > 126:
+			escaped.WriteRune('?')
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+
+	return escaped.String()
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/balance"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/orders"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrStatementBadPeriod = errors.New("statement period must end after it starts")
+)
+
+type StatementService struct {
+	balanceStorage balance.Storage
+	ordersStorage  orders.Storage
+	logger         *zap.Logger
+}
+
+// GetStatement собирает выписку за период [from, to)
+func (s *StatementService) GetStatement(
+	ctx context.Context,
+	user models.User,
+	from time.Time,
+	to time.Time,
+) (models.Statement, error) {
+	if !to.After(from) {
+		return models.Statement{}, ErrStatementBadPeriod
+	}
+
+	openingBalance, err := s.balanceStorage.GetBalanceAt(ctx, user, from)
+	if err != nil {
+		return models.Statement{}, err
+	}
+
+	entries, err := s.balanceStorage.GetPeriodHistory(ctx, user, from, to)
+	if err != nil {
+		return models.Statement{}, err
+	}
+
+	ordersCount, err := s.ordersStorage.CountUserOrders(ctx, user, models.OrdersFilter{
+		From: from,
+		To:   to,
+	})
+	if err != nil {
+		return models.Statement{}, err
+	}
+
+	statement := models.Statement{
+		Login:          user.Login,
+		From:           from,
+		To:             to,
+		OpeningBalance: openingBalance,
+		Credits:        decimal.Zero,
+		Debits:         decimal.Zero,
+		OrdersCount:    ordersCount,
+		Entries:        entries,
+	}
+
+	for _, entry := range entries {
+		if entry.Amount.IsPositive() {
+			statement.Credits = statement.Credits.Add(entry.Amount)
+		} else {
+			statement.Debits = statement.Debits.Add(entry.Amount.Neg())
+		}
+	}
+
+	statement.ClosingBalance = openingBalance.Add(statement.Credits).Sub(statement.Debits)
+
+	return statement, nil
+}
+
+func NewStatementService(
+	balanceStorage balance.Storage,
+	ordersStorage orders.Storage,
+	logger *zap.Logger,
+) *StatementService {
+	return &StatementService{
+		balanceStorage: balanceStorage,
+		ordersStorage:  ordersStorage,
+		logger:         logger,
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/mocks"
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestStatement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	logger := zap.NewExample()
+
+	user := models.User{
+		ID:    1,
+		Login: "customer",
+	}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	entries := []models.BalanceHistoryEntry{
+		{
+			BalanceChange: models.BalanceChange{
+				OrderNumber: "12345678903",
+				Amount:      decimal.RequireFromString("300.5"),
+				ProcessedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+				Type:        types.BalanceChangeTypeAccrual,
+			},
+			Balance: decimal.RequireFromString("400.5"),
+		},
+		{
+			BalanceChange: models.BalanceChange{
+				OrderNumber: "2377225624",
+				Amount:      decimal.NewFromInt(-150),
+				ProcessedAt: time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC),
+				Type:        types.BalanceChangeTypeWithdrawal,
+			},
+			Balance: decimal.RequireFromString("250.5"),
+		},
+	}
+
+	t.Run("statement totals", func(t *testing.T) {
+		balanceStorage := mocks.NewMockBalanceStorage(ctrl)
+		balanceStorage.EXPECT().GetBalanceAt(gomock.Any(), user, from).Return(decimal.NewFromInt(100), nil)
+		balanceStorage.EXPECT().GetPeriodHistory(gomock.Any(), user, from, to).Return(entries, nil)
+
+		ordersStorage := mocks.NewMockOrdersStorage(ctrl)
+		ordersStorage.EXPECT().
+			CountUserOrders(gomock.Any(), user, models.OrdersFilter{From: from, To: to}).
+			Return(2, nil)
+
+		statementService := NewStatementService(balanceStorage, ordersStorage, logger)
+
+		statement, err := statementService.GetStatement(context.Background(), user, from, to)
+		require.NoError(t, err)
+
+		assert.Equal(t, "100", statement.OpeningBalance.String())
+		assert.Equal(t, "300.5", statement.Credits.String())
+		assert.Equal(t, "150", statement.Debits.String())
+		assert.Equal(t, "250.5", statement.ClosingBalance.String())
+		assert.Equal(t, 2, statement.OrdersCount)
+	})
+
+	t.Run("bad period", func(t *testing.T) {
+		statementService := NewStatementService(mocks.NewMockBalanceStorage(ctrl), mocks.NewMockOrdersStorage(ctrl), logger)
+
+		_, err := statementService.GetStatement(context.Background(), user, to, from)
+
+		assert.ErrorIs(t, err, ErrStatementBadPeriod)
+	})
+
+	statement := models.Statement{
+		Login:          user.Login,
+		From:           from,
+		To:             to,
+		OpeningBalance: decimal.NewFromInt(100),
+		Credits:        decimal.RequireFromString("300.5"),
+		Debits:         decimal.NewFromInt(150),
+		ClosingBalance: decimal.RequireFromString("250.5"),
+		OrdersCount:    2,
+		Entries:        entries,
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := WriteStatementCSV(&buf, statement)
+		require.NoError(t, err)
+
+		assert.Equal(t, `login,from,to,opening_balance,credits,debits,closing_balance,orders
+customer,2025-01-01T00:00:00Z,2025-02-01T00:00:00Z,100.00,300.50,150.00,250.50,2
+
+processed_at,type,order,amount,balance
+2025-01-10T12:00:00Z,accrual,12345678903,300.50,400.50
+2025-01-20T12:00:00Z,withdrawal,2377225624,-150.00,250.50
+`, buf.String())
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		longStatement := statement
+		for range 100 {
+			longStatement.Entries = append(longStatement.Entries, entries[0])
+		}
+
+		err := WriteStatementPDF(&buf, longStatement)
+		require.NoError(t, err)
+
+		document := buf.String()
+
+		assert.Regexp(t, `^%PDF-1\.4\n`, document)
+		assert.Regexp(t, `%%EOF\n$`, document)
+		assert.Contains(t, document, "(Closing balance: 250.50) Tj")
+		assert.Contains(t, document, "/Count 2")
+
+		// каждая запись в таблице xref должна указывать на начало своего объекта
+		xrefOffset, err := strconv.Atoi(regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(document)[1])
+		require.NoError(t, err)
+
+		offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(document[xrefOffset:], -1)
+		require.Len(t, offsets, 7)
+
+		for i, offset := range offsets {
+			position, err := strconv.Atoi(offset[1])
+			require.NoError(t, err)
+
+			assert.Truef(t, bytes.HasPrefix(buf.Bytes()[position:], fmt.Appendf(nil, "%d 0 obj", i+1)),
+				"xref entry %d points to wrong offset", i+1)
+		}
+	})
+}
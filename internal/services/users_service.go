@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/users"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+)
+
+type UsersService struct {
+	usersStorage users.Storage
+	logger       *zap.Logger
+}
+
+func (u *UsersService) SearchUsers(ctx context.Context, login string, limit int) ([]models.User, error) {
+	return u.usersStorage.Search(ctx, login, limit)
+}
+
+func (u *UsersService) GetUser(ctx context.Context, id int64) (models.User, error) {
+	user, err := u.usersStorage.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, users.ErrUserNotFound) {
+			return models.User{}, ErrUserNotFound
+		}
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+func (u *UsersService) SetLocked(ctx context.Context, id int64, locked bool, operator models.User) error {
+	err := u.usersStorage.SetLocked(ctx, id, locked)
+	if err != nil {
+		if errors.Is(err, users.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	u.logger.Info("user lock changed",
+		zap.Int64("user_id", id),
+		zap.Int64("operator_id", operator.ID),
+		zap.Bool("locked", locked),
+	)
+
+	return nil
+}
+
+func NewUsersService(usersStorage users.Storage, logger *zap.Logger) *UsersService {
+	return &UsersService{
+		usersStorage: usersStorage,
+		logger:       logger,
+	}
+}
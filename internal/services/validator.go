@@ -5,9 +5,10 @@ import (
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
 )
 
-func NewValidate(uni *AppUni) *validator.Validate {
+func NewValidate(uni *AppUni, passwordPolicy PasswordPolicy) *validator.Validate {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 	uni.RegisterValidationTranslations(validate)
 
@@ -25,5 +26,20 @@ func NewValidate(uni *AppUni) *validator.Validate {
 		return IsValidLuhnNumber(fl.Field().String())
 	})
 
+	// min для сумм: сравнивает decimal без перевода во float
+	validate.RegisterValidation("decimal_min", func(fl validator.FieldLevel) bool {
+		amount, ok := fl.Field().Interface().(decimal.Decimal)
+		if !ok {
+			return false
+		}
+		return amount.GreaterThanOrEqual(decimal.RequireFromString(fl.Param()))
+	})
+	uni.RegisterValidationTranslation(validate, "decimal_min", "{0} must be {1} or greater")
+
+	validate.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return passwordPolicy.Check(fl.Field().String())
+	})
+	uni.RegisterValidationTranslation(validate, "password", "{0} "+passwordPolicy.Description())
+
 	return validate
 }
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const webhookSignaturePrefix = "sha256="
+
+var (
+	ErrWebhookInvalidSignature = errors.New("invalid webhook signature")
+	ErrWebhookExpired          = errors.New("webhook timestamp is outside of replay window")
+)
+
+// WebhookVerifier проверяет подпись входящих вебхуков: HMAC-SHA256 от "<timestamp>.<body>".
+// Метка времени входит в подпись, поэтому перехваченный запрос нельзя повторить после окна tolerance.
+type WebhookVerifier struct {
+	secret    []byte
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// Verify принимает метку времени в unix секундах и подпись вида sha256=<hex>
+func (v *WebhookVerifier) Verify(rawTimestamp string, signature string, body []byte) error {
+	timestamp, err := strconv.ParseInt(rawTimestamp, 10, 64)
+	if err != nil {
+		return ErrWebhookInvalidSignature
+	}
+
+	rawSignature, ok := strings.CutPrefix(signature, webhookSignaturePrefix)
+	if !ok {
+		return ErrWebhookInvalidSignature
+	}
+
+	expectedMAC, err := hex.DecodeString(rawSignature)
+	if err != nil {
+		return ErrWebhookInvalidSignature
+	}
+
+	if !hmac.Equal(v.mac(rawTimestamp, body), expectedMAC) {
+		return ErrWebhookInvalidSignature
+	}
+
+	// время проверяется после подписи, чтобы не подсказывать подбирающему метку
+	age := v.now().Sub(time.Unix(timestamp, 0))
+	if age > v.tolerance || age < -v.tolerance {
+		return ErrWebhookExpired
+	}
+
+	return nil
+}
+
+// Sign возвращает метку времени и подпись для отправителя вебхука
+func (v *WebhookVerifier) Sign(at time.Time, body []byte) (string, string) {
+	rawTimestamp := strconv.FormatInt(at.Unix(), 10)
+	return rawTimestamp, webhookSignaturePrefix + hex.EncodeToString(v.mac(rawTimestamp, body))
+}
+
+func (v *WebhookVerifier) mac(rawTimestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(rawTimestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+func NewWebhookVerifier(secret string, tolerance time.Duration) *WebhookVerifier {
+	return &WebhookVerifier{
+		secret:    []byte(secret),
+		tolerance: tolerance,
+		now:       time.Now,
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookVerifier(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	verifier := NewWebhookVerifier("secret", 5*time.Minute)
+	verifier.now = func() time.Time {
+		return now
+	}
+
+	body := []byte(`{"order": "12345678903", "status": "PROCESSED", "accrual": 500}`)
+
+	t.Run("valid signature", func(t *testing.T) {
+		timestamp, signature := verifier.Sign(now.Add(-time.Minute), body)
+
+		assert.NoError(t, verifier.Verify(timestamp, signature, body))
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		timestamp, signature := verifier.Sign(now, body)
+
+		err := verifier.Verify(timestamp, signature, []byte(`{"order": "12345678903", "status": "PROCESSED", "accrual": 5000}`))
+		assert.ErrorIs(t, err, ErrWebhookInvalidSignature)
+	})
+
+	t.Run("another secret", func(t *testing.T) {
+		timestamp, signature := NewWebhookVerifier("another", 5*time.Minute).Sign(now, body)
+
+		assert.ErrorIs(t, verifier.Verify(timestamp, signature, body), ErrWebhookInvalidSignature)
+	})
+
+	t.Run("timestamp is signed", func(t *testing.T) {
+		_, signature := verifier.Sign(now.Add(-time.Hour), body)
+		timestamp, _ := verifier.Sign(now, body)
+
+		assert.ErrorIs(t, verifier.Verify(timestamp, signature, body), ErrWebhookInvalidSignature)
+	})
+
+	t.Run("replay after window", func(t *testing.T) {
+		timestamp, signature := verifier.Sign(now.Add(-6*time.Minute), body)
+
+		assert.ErrorIs(t, verifier.Verify(timestamp, signature, body), ErrWebhookExpired)
+	})
+
+	t.Run("timestamp from future", func(t *testing.T) {
+		timestamp, signature := verifier.Sign(now.Add(6*time.Minute), body)
+
+		assert.ErrorIs(t, verifier.Verify(timestamp, signature, body), ErrWebhookExpired)
+	})
+
+	t.Run("malformed headers", func(t *testing.T) {
+		timestamp, signature := verifier.Sign(now, body)
+
+		assert.ErrorIs(t, verifier.Verify("", signature, body), ErrWebhookInvalidSignature)
+		assert.ErrorIs(t, verifier.Verify(timestamp, signature[len("sha256="):], body), ErrWebhookInvalidSignature)
+		assert.ErrorIs(t, verifier.Verify(timestamp, "sha256=zz", body), ErrWebhookInvalidSignature)
+	})
+}
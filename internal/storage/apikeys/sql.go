@@ -0,0 +1,156 @@
+package apikeys
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+type SQLStorage struct {
+	pgxpool *pgxpool.Pool
+}
+
+func (s *SQLStorage) Ping(ctx context.Context) error {
+	return s.pgxpool.Ping(ctx)
+}
+
+func (s *SQLStorage) Create(ctx context.Context, key models.APIKey, keyHash []byte) (models.APIKey, error) {
+	scopes := []string{}
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	row := s.pgxpool.QueryRow(ctx, `
+        INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes) 
+        VALUES (@user_id, @name, @prefix, @key_hash, @scopes) 
+        RETURNING id, user_id, name, prefix, scopes, created_at, last_used_at
+    `, pgx.NamedArgs{
+		"user_id":  key.UserID,
+		"name":     key.Name,
+		"prefix":   key.Prefix,
+		"key_hash": keyHash,
+		"scopes":   scopes,
+	})
+
+	return scanAPIKey(row)
+}
+
+func (s *SQLStorage) GetUserKeys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	keys := []models.APIKey{}
+
+	rows, err := s.pgxpool.Query(ctx, `
+        SELECT id, user_id, name, prefix, scopes, created_at, last_used_at FROM api_keys 
+        WHERE user_id = @user_id AND revoked_at IS NULL 
+        ORDER BY created_at DESC
+    `, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *SQLStorage) Revoke(ctx context.Context, userID int64, id string) error {
+	tag, err := s.pgxpool.Exec(ctx, `
+        UPDATE api_keys SET revoked_at = NOW() 
+        WHERE id = @id AND user_id = @user_id AND revoked_at IS NULL
+    `, pgx.NamedArgs{
+		"id":      id,
+		"user_id": userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLStorage) Use(ctx context.Context, keyHash []byte) (models.APIKey, error) {
+	row := s.pgxpool.QueryRow(ctx, `
+        UPDATE api_keys SET last_used_at = NOW() 
+        WHERE key_hash = @key_hash AND revoked_at IS NULL 
+        RETURNING id, user_id, name, prefix, scopes, created_at, last_used_at
+    `, pgx.NamedArgs{
+		"key_hash": keyHash,
+	})
+
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.APIKey{}, ErrAPIKeyNotFound
+		}
+		return models.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+func (s *SQLStorage) Close() error {
+	s.pgxpool.Close()
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (models.APIKey, error) {
+	var key models.APIKey
+	var scopes []string
+	var lastUsedAt sql.NullTime
+
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &scopes, &key.CreatedAt, &lastUsedAt)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, types.APIKeyScope(scope))
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = lastUsedAt.Time
+	}
+
+	return key, nil
+}
+
+func NewSQLStorage(ctx context.Context, databaseDSN string) (*SQLStorage, error) {
+	pool, err := pgxpool.New(ctx, databaseDSN)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := SQLStorage{
+		pgxpool: pool,
+	}
+
+	err = storage.Ping(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage, nil
+}
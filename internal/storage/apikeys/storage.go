@@ -0,0 +1,28 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+)
+
+type Storage interface {
+	Ping(ctx context.Context) error
+
+	Create(ctx context.Context, key models.APIKey, keyHash []byte) (models.APIKey, error)
+
+	// GetUserKeys возвращает неотозванные ключи пользователя
+	GetUserKeys(ctx context.Context, userID int64) ([]models.APIKey, error)
+
+	Revoke(ctx context.Context, userID int64, id string) error
+
+	// Use находит действующий ключ по хешу и отмечает время использования
+	Use(ctx context.Context, keyHash []byte) (models.APIKey, error)
+
+	Close() error
+}
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
@@ -0,0 +1,155 @@
+package balance
+
+import (
+	"context"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+// LockBalance блокирует строку баланса пользователя до конца транзакции и возвращает текущий баланс
+func LockBalance(ctx context.Context, tx pgx.Tx, userID int64) (decimal.Decimal, error) {
+	var current decimal.Decimal
+
+	_, err := tx.Exec(ctx, `
+        INSERT INTO balances (user_id) VALUES ($1)
+        ON CONFLICT (user_id) DO NOTHING
+    `, userID)
+	if err != nil {
+		return current, err
+	}
+
+	row := tx.QueryRow(ctx, `
+        SELECT current FROM balances WHERE user_id = $1 FOR UPDATE
+    `, userID)
+	err = row.Scan(&current)
+
+	return current, err
+}
+
+// ApplyChange пишет изменение в журнал и обновляет баланс в той же транзакции
+func ApplyChange(ctx context.Context, tx pgx.Tx, change models.BalanceChange) error {
+	_, err := tx.Exec(ctx, ChangeBalanceQuery, ChangeBalanceArgs(change))
+	if err != nil {
+		return err
+	}
+
+	withdrawn := decimal.Zero
+	if change.Type == types.BalanceChangeTypeWithdrawal || change.Type == types.BalanceChangeTypeRefund {
+		withdrawn = change.Amount.Neg()
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO balances (user_id, current, withdrawn, version, updated_at)
+        VALUES (@user_id, @amount, @withdrawn, 1, NOW())
+        ON CONFLICT (user_id) DO UPDATE
+        SET current = balances.current + EXCLUDED.current,
+            withdrawn = balances.withdrawn + EXCLUDED.withdrawn,
+            version = balances.version + 1,
+            updated_at = NOW()
+    `, pgx.NamedArgs{
+		"user_id":   change.UserID,
+		"amount":    change.Amount,
+		"withdrawn": withdrawn,
+	})
+
+	return err
+}
+
+// GetMismatches сверяет сохранённые балансы с журналом
+func (s *SQLStorage) GetMismatches(ctx context.Context) ([]models.BalanceMismatch, error) {
+	mismatches := []models.BalanceMismatch{}
+
+	rows, err := s.pgxpool.Query(ctx, `
+        WITH ledger AS (
+            SELECT 
+                user_id, 
+                SUM(amount) AS current,
+                SUM(CASE WHEN type IN (@withdrawal_type, @refund_type) THEN -amount ELSE 0 END) AS withdrawn
+            FROM balance_logs
+            GROUP BY user_id
+        )
+        SELECT 
+            COALESCE(balances.user_id, ledger.user_id),
+            COALESCE(balances.current, 0), COALESCE(balances.withdrawn, 0),
+            COALESCE(ledger.current, 0), COALESCE(ledger.withdrawn, 0)
+        FROM balances
+        FULL JOIN ledger ON ledger.user_id = balances.user_id
+        WHERE COALESCE(balances.current, 0) <> COALESCE(ledger.current, 0)
+            OR COALESCE(balances.withdrawn, 0) <> COALESCE(ledger.withdrawn, 0)
+        ORDER BY 1
+    `, pgx.NamedArgs{
+		"withdrawal_type": types.BalanceChangeTypeWithdrawal,
+		"refund_type":     types.BalanceChangeTypeRefund,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mismatch models.BalanceMismatch
+
+		err = rows.Scan(
+			&mismatch.UserID,
+			&mismatch.Stored.Current,
+			&mismatch.Stored.Withdrawn,
+			&mismatch.Ledger.Current,
+			&mismatch.Ledger.Withdrawn,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		mismatches = append(mismatches, mismatch)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return mismatches, nil
+}
+
+// Rebuild пересчитывает баланс пользователя по журналу
+func (s *SQLStorage) Rebuild(ctx context.Context, userID int64) error {
+	tx, err := s.pgxpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// после блокировки все параллельные изменения уже в журнале, а новые ждут коммита
+	_, err = LockBalance(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE balances
+        SET current = ledger.current,
+            withdrawn = ledger.withdrawn,
+            version = balances.version + 1,
+            updated_at = NOW()
+        FROM (
+            SELECT 
+                COALESCE(SUM(amount), 0) AS current,
+                COALESCE(SUM(CASE WHEN type IN (@withdrawal_type, @refund_type) THEN -amount ELSE 0 END), 0) AS withdrawn
+            FROM balance_logs
+            WHERE user_id = @user_id
+        ) AS ledger
+        WHERE balances.user_id = @user_id
+    `, pgx.NamedArgs{
+		"user_id":         userID,
+		"withdrawal_type": types.BalanceChangeTypeWithdrawal,
+		"refund_type":     types.BalanceChangeTypeRefund,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,190 @@
+package balance
+
+import (
+	"context"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+// currentBalanceQuery текущий баланс пользователя @user_id из balances, который
+// обновляется в одной транзакции с журналом; баланс на момент в прошлом получается вычитанием более новых записей
+const currentBalanceQuery = `COALESCE((SELECT current FROM balances WHERE user_id = @user_id), 0)`
+
+func (s *SQLStorage) GetHistory(
+	ctx context.Context,
+	user models.User,
+	filter models.BalanceHistoryFilter,
+) ([]models.BalanceHistoryEntry, error) {
+	entries := []models.BalanceHistoryEntry{}
+
+	// баланс после записи считается от текущего баланса из balances назад: из него вычитаются
+	// записи новее страницы и новее самой записи, так цена страницы не зависит от длины журнала
+	rows, err := s.pgxpool.Query(ctx, `
+        WITH page AS (
+            SELECT id, order_number, user_id, amount, processed_at, type
+            FROM balance_logs
+            WHERE user_id = @user_id
+                AND (@from::TIMESTAMP IS NULL OR processed_at >= @from::TIMESTAMP)
+                AND (@to::TIMESTAMP IS NULL OR processed_at < @to::TIMESTAMP)
+                AND (@after_at::TIMESTAMP IS NULL 
+                    OR (processed_at, id) < (@after_at::TIMESTAMP, NULLIF(@after_id, '')::UUID))
+            ORDER BY processed_at DESC, id DESC
+            LIMIT @limit
+        ), page_start AS (
+            SELECT `+currentBalanceQuery+` - COALESCE(SUM(newer.amount), 0) AS balance
+            FROM balance_logs AS newer
+            WHERE newer.user_id = @user_id
+                AND (newer.processed_at, newer.id) > (
+                    SELECT processed_at, id FROM page ORDER BY processed_at DESC, id DESC LIMIT 1
+                )
+        )
+        SELECT page.id, COALESCE(page.order_number, ''), page.user_id, page.amount, page.processed_at, page.type,
+            page_start.balance - COALESCE(SUM(page.amount) OVER (
+                ORDER BY page.processed_at DESC, page.id DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+            ), 0)
+        FROM page, page_start
+        ORDER BY page.processed_at DESC, page.id DESC
+    `, pgx.NamedArgs{
+		"user_id":  user.ID,
+		"from":     nullTime(filter.From),
+		"to":       nullTime(filter.To),
+		"after_at": nullTime(filter.After.At),
+		"after_id": filter.After.Key,
+		"limit":    filter.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.BalanceHistoryEntry
+
+		err = rows.Scan(
+			&entry.ID,
+			&entry.OrderNumber,
+			&entry.UserID,
+			&entry.Amount,
+			&entry.ProcessedAt,
+			&entry.Type,
+			&entry.Balance,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *SQLStorage) CountHistory(
+	ctx context.Context,
+	user models.User,
+	filter models.BalanceHistoryFilter,
+) (int, error) {
+	var total int
+
+	row := s.pgxpool.QueryRow(ctx, `
+        SELECT COUNT(*)
+        FROM balance_logs
+        WHERE user_id = @user_id
+            AND (@from::TIMESTAMP IS NULL OR processed_at >= @from::TIMESTAMP)
+            AND (@to::TIMESTAMP IS NULL OR processed_at < @to::TIMESTAMP)
+    `, pgx.NamedArgs{
+		"user_id": user.ID,
+		"from":    nullTime(filter.From),
+		"to":      nullTime(filter.To),
+	})
+	err := row.Scan(&total)
+
+	return total, err
+}
+
+func (s *SQLStorage) GetPeriodHistory(
+	ctx context.Context,
+	user models.User,
+	from time.Time,
+	to time.Time,
+) ([]models.BalanceHistoryEntry, error) {
+	entries := []models.BalanceHistoryEntry{}
+
+	// как и в GetHistory, баланс считается назад от текущего, а не с начала журнала
+	rows, err := s.pgxpool.Query(ctx, `
+        WITH period_end AS (
+            SELECT `+currentBalanceQuery+` - COALESCE(SUM(amount), 0) AS balance
+            FROM balance_logs
+            WHERE user_id = @user_id AND processed_at >= @to
+        )
+        SELECT id, COALESCE(order_number, ''), user_id, amount, processed_at, type,
+            period_end.balance - COALESCE(SUM(amount) OVER (
+                ORDER BY processed_at DESC, id DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+            ), 0)
+        FROM balance_logs, period_end
+        WHERE user_id = @user_id AND processed_at >= @from AND processed_at < @to
+        ORDER BY processed_at, id
+    `, pgx.NamedArgs{
+		"user_id": user.ID,
+		"from":    from,
+		"to":      to,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.BalanceHistoryEntry
+
+		err = rows.Scan(
+			&entry.ID,
+			&entry.OrderNumber,
+			&entry.UserID,
+			&entry.Amount,
+			&entry.ProcessedAt,
+			&entry.Type,
+			&entry.Balance,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *SQLStorage) GetBalanceAt(
+	ctx context.Context,
+	user models.User,
+	at time.Time,
+) (decimal.Decimal, error) {
+	var balance decimal.Decimal
+
+	row := s.pgxpool.QueryRow(ctx, `
+        SELECT `+currentBalanceQuery+` - COALESCE(SUM(amount), 0)
+        FROM balance_logs
+        WHERE user_id = @user_id AND processed_at >= @at
+    `, pgx.NamedArgs{
+		"user_id": user.ID,
+		"at":      at,
+	})
+	err := row.Scan(&balance)
+
+	return balance, err
+}
@@ -2,10 +2,15 @@ package balance
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
+	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/shopspring/decimal"
@@ -17,13 +22,31 @@ type SQLStorage struct {
 
 const (
 	ChangeBalanceQuery = `
-        INSERT INTO balance_logs (id, order_number, user_id, amount, processed_at) 
-        VALUES (DEFAULT, @order_number, @user_id, @amount, DEFAULT)
+        INSERT INTO balance_logs (id, order_number, user_id, amount, processed_at, type, reason, reference, operator_id, refund_of, idempotency_key) 
+        VALUES (DEFAULT, NULLIF(@order_number, ''), @user_id, @amount, DEFAULT, @type, @reason, @reference, NULLIF(@operator_id::BIGINT, 0), NULLIF(@refund_of, '')::UUID, NULLIF(@idempotency_key, ''))
     `
 )
 
+func ChangeBalanceArgs(change models.BalanceChange) pgx.NamedArgs {
+	return pgx.NamedArgs{
+		"order_number":    change.OrderNumber,
+		"user_id":         change.UserID,
+		"amount":          change.Amount,
+		"type":            change.Type,
+		"reason":          change.Reason,
+		"reference":       change.Reference,
+		"operator_id":     change.OperatorID,
+		"refund_of":       change.RefundOf,
+		"idempotency_key": change.IdempotencyKey,
+	}
+}
+
 var (
-	ErrNotEnoughFunds = errors.New("not enough funds on user balance")
+	ErrNotEnoughFunds     = errors.New("not enough funds on user balance")
+	ErrWithdrawalNotFound = errors.New("withdrawal not found")
+	ErrAlreadyRefunded    = errors.New("withdrawal already refunded")
+	ErrWithdrawalExists   = errors.New("withdrawal for order already exists")
+	ErrIdempotencyKeyUsed = errors.New("idempotency key already used")
 )
 
 func (s *SQLStorage) Ping(ctx context.Context) error {
@@ -31,55 +54,184 @@ func (s *SQLStorage) Ping(ctx context.Context) error {
 }
 
 func (s *SQLStorage) Withdraw(ctx context.Context, withdraw models.BalanceChange) error {
+	withdraw.Type = types.BalanceChangeTypeWithdrawal
+	return s.change(ctx, withdraw)
+}
+
+func (s *SQLStorage) Adjust(ctx context.Context, adjustment models.BalanceChange) error {
+	adjustment.Type = types.BalanceChangeTypeAdjustment
+	return s.change(ctx, adjustment)
+}
+
+// change добавляет запись в журнал, не допуская ухода баланса в минус
+func (s *SQLStorage) change(ctx context.Context, change models.BalanceChange) error {
 	tx, err := s.pgxpool.Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 
-	var balance decimal.Decimal
-
-	row := tx.QueryRow(ctx, `
-        SELECT COALESCE(SUM(amount), 0) 
-        FROM (
-            SELECT amount FROM balance_logs 
-            WHERE user_id = $1
-            FOR UPDATE
-        )
-    `, withdraw.UserID)
-	err = row.Scan(&balance)
+	balance, err := LockBalance(ctx, tx, change.UserID)
 	if err != nil {
 		return err
 	}
 
-	if balance.LessThan(withdraw.Amount) {
+	if balance.Add(change.Amount).IsNegative() {
 		return ErrNotEnoughFunds
 	}
 
-	_, err = tx.Exec(ctx, ChangeBalanceQuery, pgx.NamedArgs{
-		"order_number": withdraw.OrderNumber,
-		"user_id":      withdraw.UserID,
-		"amount":       withdraw.Amount,
-	})
+	err = ApplyChange(ctx, tx, change)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			switch pgErr.ConstraintName {
+			case "idx_balance_logs_withdrawal_order_number":
+				return ErrWithdrawalExists
+			case "idx_balance_logs_idempotency_key":
+				return ErrIdempotencyKeyUsed
+			}
+		}
 		return err
 	}
 
 	return tx.Commit(ctx)
 }
 
+func (s *SQLStorage) GetOrderWithdrawal(
+	ctx context.Context,
+	orderNumber string,
+) (models.BalanceChange, error) {
+	var withdrawal models.BalanceChange
+
+	row := s.pgxpool.QueryRow(ctx, `
+        SELECT id, order_number, user_id, amount, processed_at, type, COALESCE(idempotency_key, '') 
+        FROM balance_logs 
+        WHERE order_number = @order_number AND type = @type AND duplicate_of IS NULL
+    `, pgx.NamedArgs{
+		"order_number": orderNumber,
+		"type":         types.BalanceChangeTypeWithdrawal,
+	})
+	err := row.Scan(
+		&withdrawal.ID,
+		&withdrawal.OrderNumber,
+		&withdrawal.UserID,
+		&withdrawal.Amount,
+		&withdrawal.ProcessedAt,
+		&withdrawal.Type,
+		&withdrawal.IdempotencyKey,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.BalanceChange{}, ErrWithdrawalNotFound
+		}
+		return models.BalanceChange{}, err
+	}
+
+	return withdrawal, nil
+}
+
+// Refund возвращает баллы по последнему списанию за заказ, повторный возврат не допускается
+func (s *SQLStorage) Refund(ctx context.Context, refund models.BalanceChange) (models.BalanceChange, error) {
+	tx, err := s.pgxpool.Begin(ctx)
+	if err != nil {
+		return models.BalanceChange{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var withdrawalID string
+	var withdrawalAmount decimal.Decimal
+	var refunded bool
+
+	// блокировка списания не даёт двум параллельным возвратам пройти проверку одновременно
+	row := tx.QueryRow(ctx, `
+        SELECT withdrawals.id, withdrawals.amount, EXISTS (
+            SELECT 1 FROM balance_logs AS refunds WHERE refunds.refund_of = withdrawals.id
+        )
+        FROM balance_logs AS withdrawals
+        WHERE withdrawals.user_id = @user_id 
+            AND withdrawals.order_number = @order_number 
+            AND withdrawals.type = @type
+            AND withdrawals.duplicate_of IS NULL
+        ORDER BY withdrawals.processed_at DESC
+        LIMIT 1
+        FOR UPDATE
+    `, pgx.NamedArgs{
+		"user_id":      refund.UserID,
+		"order_number": refund.OrderNumber,
+		"type":         types.BalanceChangeTypeWithdrawal,
+	})
+	err = row.Scan(&withdrawalID, &withdrawalAmount, &refunded)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.BalanceChange{}, ErrWithdrawalNotFound
+		}
+		return models.BalanceChange{}, err
+	}
+
+	if refunded {
+		return models.BalanceChange{}, ErrAlreadyRefunded
+	}
+
+	refund.Type = types.BalanceChangeTypeRefund
+	refund.Amount = withdrawalAmount.Neg()
+	refund.RefundOf = withdrawalID
+
+	err = ApplyChange(ctx, tx, refund)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return models.BalanceChange{}, ErrAlreadyRefunded
+		}
+		return models.BalanceChange{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return models.BalanceChange{}, err
+	}
+
+	return refund, nil
+}
+
+const withdrawalsFilterCondition = `
+    withdrawals.user_id = @user_id 
+    AND withdrawals.type = @type
+    AND (@from::TIMESTAMP IS NULL OR withdrawals.processed_at >= @from::TIMESTAMP)
+    AND (@to::TIMESTAMP IS NULL OR withdrawals.processed_at < @to::TIMESTAMP)
+`
+
+func withdrawalsFilterArgs(user models.User, filter models.WithdrawalsFilter) pgx.NamedArgs {
+	return pgx.NamedArgs{
+		"user_id": user.ID,
+		"type":    types.BalanceChangeTypeWithdrawal,
+		"from":    nullTime(filter.From),
+		"to":      nullTime(filter.To),
+	}
+}
+
 func (s *SQLStorage) GetWithdrawals(
 	ctx context.Context,
 	user models.User,
+	filter models.WithdrawalsFilter,
 ) ([]models.BalanceChange, error) {
 	withdrawals := []models.BalanceChange{}
 
+	args := withdrawalsFilterArgs(user, filter)
+	args["after_at"] = nullTime(filter.After.At)
+	args["after_id"] = filter.After.Key
+	args["limit"] = filter.Limit
+
 	rows, err := s.pgxpool.Query(ctx, `
-        SELECT order_number, user_id, amount, processed_at 
-        FROM balance_logs 
-        WHERE user_id = $1 AND amount < 0
-        ORDER BY processed_at DESC
-    `, user.ID)
+        SELECT withdrawals.id, withdrawals.order_number, withdrawals.user_id, withdrawals.amount, 
+            withdrawals.processed_at, withdrawals.type, refunds.processed_at
+        FROM balance_logs AS withdrawals
+        LEFT JOIN balance_logs AS refunds ON refunds.refund_of = withdrawals.id
+        WHERE `+withdrawalsFilterCondition+`
+            AND (@after_at::TIMESTAMP IS NULL 
+                OR (withdrawals.processed_at, withdrawals.id) < (@after_at::TIMESTAMP, NULLIF(@after_id, '')::UUID))
+        ORDER BY withdrawals.processed_at DESC, withdrawals.id DESC
+        LIMIT NULLIF(@limit::INT, 0)
+    `, args)
 	if err != nil {
 		return nil, err
 	}
@@ -87,17 +239,25 @@ func (s *SQLStorage) GetWithdrawals(
 
 	for rows.Next() {
 		var balanceChange models.BalanceChange
+		var refundedAt *time.Time
 
 		err = rows.Scan(
+			&balanceChange.ID,
 			&balanceChange.OrderNumber,
 			&balanceChange.UserID,
 			&balanceChange.Amount,
 			&balanceChange.ProcessedAt,
+			&balanceChange.Type,
+			&refundedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
 
+		if refundedAt != nil {
+			balanceChange.RefundedAt = *refundedAt
+		}
+
 		withdrawals = append(withdrawals, balanceChange)
 	}
 
@@ -109,6 +269,73 @@ func (s *SQLStorage) GetWithdrawals(
 	return withdrawals, nil
 }
 
+func (s *SQLStorage) GetWithdrawalsSummary(
+	ctx context.Context,
+	user models.User,
+	filter models.WithdrawalsFilter,
+) (models.WithdrawalsSummary, error) {
+	var summary models.WithdrawalsSummary
+
+	row := s.pgxpool.QueryRow(ctx, `
+        SELECT COUNT(*), 
+            COALESCE(SUM(-withdrawals.amount), 0),
+            COALESCE(SUM(-withdrawals.amount) FILTER (WHERE EXISTS (
+                SELECT 1 FROM balance_logs AS refunds WHERE refunds.refund_of = withdrawals.id
+            )), 0)
+        FROM balance_logs AS withdrawals
+        WHERE `+withdrawalsFilterCondition, withdrawalsFilterArgs(user, filter))
+	err := row.Scan(&summary.Count, &summary.Sum, &summary.Refunded)
+
+	return summary, err
+}
+
+func (s *SQLStorage) GetAdjustments(
+	ctx context.Context,
+	user models.User,
+) ([]models.BalanceChange, error) {
+	adjustments := []models.BalanceChange{}
+
+	rows, err := s.pgxpool.Query(ctx, `
+        SELECT user_id, amount, processed_at, type, reason, reference, COALESCE(operator_id, 0) 
+        FROM balance_logs 
+        WHERE user_id = @user_id AND type = @type
+        ORDER BY processed_at DESC
+    `, pgx.NamedArgs{
+		"user_id": user.ID,
+		"type":    types.BalanceChangeTypeAdjustment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var balanceChange models.BalanceChange
+
+		err = rows.Scan(
+			&balanceChange.UserID,
+			&balanceChange.Amount,
+			&balanceChange.ProcessedAt,
+			&balanceChange.Type,
+			&balanceChange.Reason,
+			&balanceChange.Reference,
+			&balanceChange.OperatorID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		adjustments = append(adjustments, balanceChange)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustments, nil
+}
+
 func (s *SQLStorage) GetBalance(
 	ctx context.Context,
 	user models.User,
@@ -116,15 +343,28 @@ func (s *SQLStorage) GetBalance(
 	var balance models.Balance
 
 	row := s.pgxpool.QueryRow(ctx, `
-        SELECT 
-            COALESCE(SUM(amount), 0) AS current,
-            COALESCE(SUM(CASE WHEN amount < 0 THEN ABS(amount) ELSE 0 END), 0) AS withdrawn
-        FROM balance_logs 
+        SELECT current, withdrawn 
+        FROM balances 
         WHERE user_id = $1
     `, user.ID)
 	err := row.Scan(&balance.Current, &balance.Withdrawn)
+	if err != nil {
+		// строка баланса появляется с первым изменением
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Balance{}, nil
+		}
+		return models.Balance{}, err
+	}
 
-	return balance, err
+	return balance, nil
+}
+
+// nullTime передаёт нулевое время как NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
 }
 
 func (s *SQLStorage) Close() error {
@@ -2,8 +2,10 @@ package balance
 
 import (
 	"context"
+	"time"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/shopspring/decimal"
 )
 
 type Storage interface {
@@ -11,9 +13,33 @@ type Storage interface {
 
 	Withdraw(ctx context.Context, withdraw models.BalanceChange) error
 
+	Adjust(ctx context.Context, adjustment models.BalanceChange) error
+
+	Refund(ctx context.Context, refund models.BalanceChange) (models.BalanceChange, error)
+
 	GetBalance(ctx context.Context, user models.User) (models.Balance, error)
 
-	GetWithdrawals(ctx context.Context, user models.User) ([]models.BalanceChange, error)
+	// GetWithdrawals возвращает списания пользователя от новых к старым
+	GetWithdrawals(ctx context.Context, user models.User, filter models.WithdrawalsFilter) ([]models.BalanceChange, error)
+
+	// GetWithdrawalsSummary считает итоги по фильтру без учёта курсора и лимита
+	GetWithdrawalsSummary(ctx context.Context, user models.User, filter models.WithdrawalsFilter) (models.WithdrawalsSummary, error)
+
+	GetOrderWithdrawal(ctx context.Context, orderNumber string) (models.BalanceChange, error)
+
+	GetAdjustments(ctx context.Context, user models.User) ([]models.BalanceChange, error)
+
+	// GetHistory возвращает все записи журнала пользователя от новых к старым с балансом после каждой
+	GetHistory(ctx context.Context, user models.User, filter models.BalanceHistoryFilter) ([]models.BalanceHistoryEntry, error)
+
+	// CountHistory считает записи журнала по фильтру без учёта курсора и лимита
+	CountHistory(ctx context.Context, user models.User, filter models.BalanceHistoryFilter) (int, error)
+
+	// GetPeriodHistory возвращает записи журнала за период [from, to) от старых к новым
+	GetPeriodHistory(ctx context.Context, user models.User, from time.Time, to time.Time) ([]models.BalanceHistoryEntry, error)
+
+	// GetBalanceAt возвращает баланс пользователя на момент at
+	GetBalanceAt(ctx context.Context, user models.User, at time.Time) (decimal.Decimal, error)
 
 	Close() error
 }
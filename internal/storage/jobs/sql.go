@@ -0,0 +1,297 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+type SQLStorage struct {
+	pgxpool *pgxpool.Pool
+}
+
+func (s *SQLStorage) Ping(ctx context.Context) error {
+	return s.pgxpool.Ping(ctx)
+}
+
+func (s *SQLStorage) Push(ctx context.Context, job models.OrderJob) error {
+	_, err := s.pgxpool.Exec(ctx, `
+        INSERT INTO order_jobs (order_number, job_name, attempts, run_at, created_at)
+        VALUES (@order_number, @job_name, DEFAULT, NOW(), DEFAULT)
+        ON CONFLICT (order_number, job_name) DO NOTHING
+    `, pgx.NamedArgs{
+		"order_number": job.Order.OrderNumber,
+		"job_name":     job.JobName,
+	})
+	return err
+}
+
+func (s *SQLStorage) Claim(
+	ctx context.Context,
+	jobName string,
+	lease time.Duration,
+) (models.OrderJob, error) {
+	job := models.OrderJob{
+		JobName: jobName,
+	}
+
+	// SKIP LOCKED позволяет нескольким репликам разбирать очередь без блокировок друг друга,
+	// а сдвиг run_at на время lease прячет задачу, пока она обрабатывается
+	row := s.pgxpool.QueryRow(ctx, `
+        WITH next_job AS (
+            SELECT order_number, job_name
+            FROM order_jobs
+            WHERE job_name = @job_name AND run_at <= NOW() AND failed_at IS NULL
+            ORDER BY run_at
+            LIMIT 1
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE order_jobs
+        SET attempts = order_jobs.attempts + 1,
+            run_at = NOW() + make_interval(secs => @lease_seconds)
+        FROM next_job, orders
+        WHERE order_jobs.order_number = next_job.order_number
+            AND order_jobs.job_name = next_job.job_name
+            AND orders.number = order_jobs.order_number
+        RETURNING orders.number, orders.user_id, orders.status, orders.accrual, orders.uploaded_at,
+            order_jobs.attempts, order_jobs.run_at, order_jobs.last_error
+    `, pgx.NamedArgs{
+		"job_name":      jobName,
+		"lease_seconds": lease.Seconds(),
+	})
+	err := row.Scan(
+		&job.Order.OrderNumber,
+		&job.Order.UserID,
+		&job.Order.Status,
+		&job.Order.Accrual,
+		&job.Order.UploadedAt,
+		&job.Attempts,
+		&job.RunAt,
+		&job.LastError,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.OrderJob{}, ErrNoJobs
+		}
+		return models.OrderJob{}, err
+	}
+
+	return job, nil
+}
+
+func (s *SQLStorage) Complete(ctx context.Context, job models.OrderJob) error {
+	_, err := s.pgxpool.Exec(ctx, `
+        DELETE FROM order_jobs
+        WHERE order_number = @order_number AND job_name = @job_name
+    `, pgx.NamedArgs{
+		"order_number": job.Order.OrderNumber,
+		"job_name":     job.JobName,
+	})
+	return err
+}
+
+func (s *SQLStorage) Reschedule(
+	ctx context.Context,
+	job models.OrderJob,
+	delay time.Duration,
+) error {
+	_, err := s.pgxpool.Exec(ctx, `
+        UPDATE order_jobs
+        SET run_at = NOW() + make_interval(secs => @delay_seconds), last_error = @last_error
+        WHERE order_number = @order_number AND job_name = @job_name
+    `, pgx.NamedArgs{
+		"order_number":  job.Order.OrderNumber,
+		"job_name":      job.JobName,
+		"delay_seconds": delay.Seconds(),
+		"last_error":    job.LastError,
+	})
+	return err
+}
+
+func (s *SQLStorage) Postpone(
+	ctx context.Context,
+	job models.OrderJob,
+	delay time.Duration,
+) error {
+	_, err := s.pgxpool.Exec(ctx, `
+        UPDATE order_jobs
+        SET run_at = NOW() + make_interval(secs => @delay_seconds),
+            attempts = GREATEST(attempts - 1, 0)
+        WHERE order_number = @order_number AND job_name = @job_name
+    `, pgx.NamedArgs{
+		"order_number":  job.Order.OrderNumber,
+		"job_name":      job.JobName,
+		"delay_seconds": delay.Seconds(),
+	})
+	return err
+}
+
+func (s *SQLStorage) Fail(ctx context.Context, job models.OrderJob) error {
+	_, err := s.pgxpool.Exec(ctx, `
+        UPDATE order_jobs
+        SET failed_at = NOW(), last_error = @last_error
+        WHERE order_number = @order_number AND job_name = @job_name
+    `, pgx.NamedArgs{
+		"order_number": job.Order.OrderNumber,
+		"job_name":     job.JobName,
+		"last_error":   job.LastError,
+	})
+	return err
+}
+
+func (s *SQLStorage) GetFailed(ctx context.Context, limit int) ([]models.OrderJob, error) {
+	failedJobs := []models.OrderJob{}
+
+	rows, err := s.pgxpool.Query(ctx, `
+        SELECT orders.number, orders.user_id, orders.status, orders.accrual, orders.uploaded_at,
+            order_jobs.job_name, order_jobs.attempts, order_jobs.run_at,
+            order_jobs.last_error, order_jobs.failed_at
+        FROM order_jobs
+        JOIN orders ON orders.number = order_jobs.order_number
+        WHERE order_jobs.failed_at IS NOT NULL AND order_jobs.discarded_at IS NULL
+        ORDER BY order_jobs.failed_at DESC
+        LIMIT $1
+    `, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		job, err := scanFailedJob(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		failedJobs = append(failedJobs, job)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return failedJobs, nil
+}
+
+func (s *SQLStorage) GetFailedJob(
+	ctx context.Context,
+	orderNumber string,
+	jobName string,
+) (models.OrderJob, error) {
+	row := s.pgxpool.QueryRow(ctx, `
+        SELECT orders.number, orders.user_id, orders.status, orders.accrual, orders.uploaded_at,
+            order_jobs.job_name, order_jobs.attempts, order_jobs.run_at,
+            order_jobs.last_error, order_jobs.failed_at
+        FROM order_jobs
+        JOIN orders ON orders.number = order_jobs.order_number
+        WHERE order_jobs.order_number = @order_number
+            AND order_jobs.job_name = @job_name
+            AND order_jobs.failed_at IS NOT NULL
+            AND order_jobs.discarded_at IS NULL
+    `, pgx.NamedArgs{
+		"order_number": orderNumber,
+		"job_name":     jobName,
+	})
+
+	job, err := scanFailedJob(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.OrderJob{}, ErrJobNotFound
+		}
+		return models.OrderJob{}, err
+	}
+
+	return job, nil
+}
+
+func (s *SQLStorage) Requeue(ctx context.Context, orderNumber string, jobName string) error {
+	tag, err := s.pgxpool.Exec(ctx, `
+        UPDATE order_jobs
+        SET failed_at = NULL, attempts = 0, run_at = NOW()
+        WHERE order_number = @order_number AND job_name = @job_name 
+            AND failed_at IS NOT NULL AND discarded_at IS NULL
+    `, pgx.NamedArgs{
+		"order_number": orderNumber,
+		"job_name":     jobName,
+	})
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrJobNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLStorage) Discard(ctx context.Context, orderNumber string, jobName string) error {
+	// строка остаётся отметкой, иначе восстановление после рестарта снова поставит заказ в очередь
+	tag, err := s.pgxpool.Exec(ctx, `
+        UPDATE order_jobs
+        SET discarded_at = NOW()
+        WHERE order_number = @order_number AND job_name = @job_name 
+            AND failed_at IS NOT NULL AND discarded_at IS NULL
+    `, pgx.NamedArgs{
+		"order_number": orderNumber,
+		"job_name":     jobName,
+	})
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrJobNotFound
+	}
+
+	return nil
+}
+
+func scanFailedJob(row pgx.Row) (models.OrderJob, error) {
+	var job models.OrderJob
+
+	err := row.Scan(
+		&job.Order.OrderNumber,
+		&job.Order.UserID,
+		&job.Order.Status,
+		&job.Order.Accrual,
+		&job.Order.UploadedAt,
+		&job.JobName,
+		&job.Attempts,
+		&job.RunAt,
+		&job.LastError,
+		&job.FailedAt,
+	)
+
+	return job, err
+}
+
+func (s *SQLStorage) Close() error {
+	s.pgxpool.Close()
+	return nil
+}
+
+func NewSQLStorage(ctx context.Context, databaseDSN string) (*SQLStorage, error) {
+	pool, err := pgxpool.New(ctx, databaseDSN)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := SQLStorage{
+		pgxpool: pool,
+	}
+
+	err = storage.Ping(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage, nil
+}
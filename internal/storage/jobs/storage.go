@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+)
+
+type Storage interface {
+	Ping(ctx context.Context) error
+
+	Push(ctx context.Context, job models.OrderJob) error
+
+	// Claim захватывает готовую к запуску задачу на время lease.
+	// Если воркер не успеет её завершить, задача станет доступна другим воркерам.
+	Claim(ctx context.Context, jobName string, lease time.Duration) (models.OrderJob, error)
+
+	Complete(ctx context.Context, job models.OrderJob) error
+
+	// Reschedule откладывает задачу после неудачной попытки
+	Reschedule(ctx context.Context, job models.OrderJob, delay time.Duration) error
+
+	// Postpone откладывает задачу, не засчитывая попытку
+	Postpone(ctx context.Context, job models.OrderJob, delay time.Duration) error
+
+	// Fail переводит задачу в dead letter, откуда её заберёт только оператор
+	Fail(ctx context.Context, job models.OrderJob) error
+
+	GetFailed(ctx context.Context, limit int) ([]models.OrderJob, error)
+
+	GetFailedJob(ctx context.Context, orderNumber string, jobName string) (models.OrderJob, error)
+
+	// Requeue возвращает задачу из dead letter в очередь с обнулёнными попытками
+	Requeue(ctx context.Context, orderNumber string, jobName string) error
+
+	// Discard убирает задачу из dead letter насовсем, ни очередь, ни восстановление её больше не берут
+	Discard(ctx context.Context, orderNumber string, jobName string) error
+
+	Close() error
+}
+
+var (
+	ErrNoJobs      = errors.New("no jobs ready to run")
+	ErrJobNotFound = errors.New("job not found")
+)
@@ -0,0 +1,123 @@
+package loginattempts
+
+import (
+	"context"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+type SQLStorage struct {
+	pgxpool *pgxpool.Pool
+}
+
+func (s *SQLStorage) Ping(ctx context.Context) error {
+	return s.pgxpool.Ping(ctx)
+}
+
+func (s *SQLStorage) GetBlockedFor(ctx context.Context, keys []models.LoginAttemptKey) (time.Duration, error) {
+	kinds := []string{}
+	values := []string{}
+	for _, key := range keys {
+		kinds = append(kinds, string(key.Kind))
+		values = append(values, key.Value)
+	}
+
+	var seconds float64
+
+	row := s.pgxpool.QueryRow(ctx, `
+        SELECT COALESCE(MAX(EXTRACT(EPOCH FROM blocked_until - NOW())), 0)::FLOAT8 
+        FROM login_attempts 
+        WHERE (kind, value) IN (SELECT * FROM unnest(@kinds::VARCHAR[], @values::VARCHAR[])) 
+            AND blocked_until > NOW()
+    `, pgx.NamedArgs{
+		"kinds":  kinds,
+		"values": values,
+	})
+	err := row.Scan(&seconds)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func (s *SQLStorage) AddFailure(
+	ctx context.Context,
+	key models.LoginAttemptKey,
+	window time.Duration,
+) (int, error) {
+	var failures int
+
+	row := s.pgxpool.QueryRow(ctx, `
+        INSERT INTO login_attempts (kind, value, failures, last_failed_at) 
+        VALUES (@kind, @value, 1, NOW()) 
+        ON CONFLICT (kind, value) DO UPDATE 
+        SET failures = CASE 
+                WHEN login_attempts.last_failed_at < NOW() - make_interval(secs => @window_seconds) THEN 1 
+                ELSE login_attempts.failures + 1 
+            END, 
+            last_failed_at = NOW() 
+        RETURNING failures
+    `, pgx.NamedArgs{
+		"kind":           key.Kind,
+		"value":          key.Value,
+		"window_seconds": window.Seconds(),
+	})
+	err := row.Scan(&failures)
+	if err != nil {
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+func (s *SQLStorage) Block(ctx context.Context, key models.LoginAttemptKey, duration time.Duration) error {
+	_, err := s.pgxpool.Exec(ctx, `
+        UPDATE login_attempts 
+        SET blocked_until = GREATEST(blocked_until, NOW() + make_interval(secs => @seconds)) 
+        WHERE kind = @kind AND value = @value
+    `, pgx.NamedArgs{
+		"kind":    key.Kind,
+		"value":   key.Value,
+		"seconds": duration.Seconds(),
+	})
+	return err
+}
+
+func (s *SQLStorage) Reset(ctx context.Context, key models.LoginAttemptKey) error {
+	_, err := s.pgxpool.Exec(ctx, `
+        DELETE FROM login_attempts 
+        WHERE kind = @kind AND value = @value
+    `, pgx.NamedArgs{
+		"kind":  key.Kind,
+		"value": key.Value,
+	})
+	return err
+}
+
+func (s *SQLStorage) Close() error {
+	s.pgxpool.Close()
+	return nil
+}
+
+func NewSQLStorage(ctx context.Context, databaseDSN string) (*SQLStorage, error) {
+	pool, err := pgxpool.New(ctx, databaseDSN)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := SQLStorage{
+		pgxpool: pool,
+	}
+
+	err = storage.Ping(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage, nil
+}
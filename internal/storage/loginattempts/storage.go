@@ -0,0 +1,25 @@
+package loginattempts
+
+import (
+	"context"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+)
+
+type Storage interface {
+	Ping(ctx context.Context) error
+
+	// GetBlockedFor возвращает, сколько ещё действует самая долгая блокировка из ключей
+	GetBlockedFor(ctx context.Context, keys []models.LoginAttemptKey) (time.Duration, error)
+
+	// AddFailure увеличивает счётчик неудачных попыток и возвращает его.
+	// Если последняя неудача была раньше window, счёт начинается заново.
+	AddFailure(ctx context.Context, key models.LoginAttemptKey, window time.Duration) (int, error)
+
+	Block(ctx context.Context, key models.LoginAttemptKey, duration time.Duration) error
+
+	Reset(ctx context.Context, key models.LoginAttemptKey) error
+
+	Close() error
+}
@@ -1,3 +1,3 @@
+DROP TABLE balance_logs;
 DROP TABLE orders;
 DROP TABLE users;
-DROP TABLE balance;
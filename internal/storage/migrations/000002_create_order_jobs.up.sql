@@ -0,0 +1,17 @@
+CREATE TABLE order_jobs (
+    order_number VARCHAR(30) NOT NULL,
+    job_name VARCHAR(50) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    run_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (order_number, job_name),
+
+    CONSTRAINT fk_order_jobs_order_number
+    FOREIGN KEY (order_number)
+    REFERENCES orders (number)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_order_jobs_job_name_run_at ON order_jobs (job_name, run_at);
@@ -0,0 +1,6 @@
+DROP INDEX idx_order_jobs_job_name_run_at;
+CREATE INDEX idx_order_jobs_job_name_run_at ON order_jobs (job_name, run_at);
+
+ALTER TABLE order_jobs
+    DROP COLUMN failed_at,
+    DROP COLUMN last_error;
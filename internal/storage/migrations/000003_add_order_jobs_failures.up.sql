@@ -0,0 +1,6 @@
+ALTER TABLE order_jobs
+    ADD COLUMN last_error TEXT NOT NULL DEFAULT '',
+    ADD COLUMN failed_at TIMESTAMP NULL;
+
+DROP INDEX idx_order_jobs_job_name_run_at;
+CREATE INDEX idx_order_jobs_job_name_run_at ON order_jobs (job_name, run_at) WHERE failed_at IS NULL;
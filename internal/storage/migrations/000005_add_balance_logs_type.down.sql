@@ -0,0 +1,11 @@
+DROP INDEX idx_balance_logs_user_id_type;
+
+DELETE FROM balance_logs WHERE order_number IS NULL;
+
+ALTER TABLE balance_logs
+    DROP CONSTRAINT fk_balance_operator_id,
+    DROP COLUMN operator_id,
+    DROP COLUMN reference,
+    DROP COLUMN reason,
+    DROP COLUMN type,
+    ALTER COLUMN order_number SET NOT NULL;
@@ -0,0 +1,17 @@
+ALTER TABLE balance_logs
+    ADD COLUMN type VARCHAR(20) NOT NULL DEFAULT 'accrual',
+    ADD COLUMN reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN reference VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN operator_id BIGINT NULL,
+    ALTER COLUMN order_number DROP NOT NULL,
+    ADD CONSTRAINT fk_balance_operator_id
+    FOREIGN KEY (operator_id)
+    REFERENCES users (id)
+    ON UPDATE CASCADE
+    ON DELETE RESTRICT;
+
+UPDATE balance_logs SET type = 'withdrawal' WHERE amount < 0;
+
+ALTER TABLE balance_logs ALTER COLUMN type DROP DEFAULT;
+
+CREATE INDEX idx_balance_logs_user_id_type ON balance_logs (user_id, type);
@@ -0,0 +1,7 @@
+DROP INDEX idx_balance_logs_refund_of;
+
+DELETE FROM balance_logs WHERE type = 'refund';
+
+ALTER TABLE balance_logs
+    DROP CONSTRAINT fk_balance_refund_of,
+    DROP COLUMN refund_of;
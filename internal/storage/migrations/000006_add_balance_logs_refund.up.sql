@@ -0,0 +1,10 @@
+ALTER TABLE balance_logs
+    ADD COLUMN refund_of UUID NULL,
+    ADD CONSTRAINT fk_balance_refund_of
+    FOREIGN KEY (refund_of)
+    REFERENCES balance_logs (id)
+    ON UPDATE CASCADE
+    ON DELETE RESTRICT;
+
+-- у списания может быть только один возврат
+CREATE UNIQUE INDEX idx_balance_logs_refund_of ON balance_logs (refund_of) WHERE refund_of IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX idx_balance_logs_withdrawal_order_number;
+
+ALTER TABLE balance_logs DROP COLUMN duplicate_of;
+
+DROP INDEX idx_balance_logs_idempotency_key;
+
+ALTER TABLE balance_logs DROP COLUMN idempotency_key;
@@ -0,0 +1,22 @@
+ALTER TABLE balance_logs ADD COLUMN idempotency_key VARCHAR(100) NULL;
+
+CREATE UNIQUE INDEX idx_balance_logs_idempotency_key ON balance_logs (user_id, idempotency_key) 
+WHERE idempotency_key IS NOT NULL;
+
+-- раньше повторный запрос мог списать баллы за один заказ несколько раз.
+-- Такие списания остаются в журнале как есть, только помечаются ссылкой на первое списание
+-- и не попадают под уникальный индекс, который действует для всех новых списаний
+ALTER TABLE balance_logs ADD COLUMN duplicate_of UUID NULL;
+
+UPDATE balance_logs
+SET duplicate_of = withdrawals.first_id
+FROM (
+    SELECT id, 
+        FIRST_VALUE(id) OVER (PARTITION BY order_number ORDER BY processed_at, id) AS first_id
+    FROM balance_logs
+    WHERE type = 'withdrawal'
+) AS withdrawals
+WHERE balance_logs.id = withdrawals.id AND withdrawals.id <> withdrawals.first_id;
+
+CREATE UNIQUE INDEX idx_balance_logs_withdrawal_order_number ON balance_logs (order_number) 
+WHERE type = 'withdrawal' AND duplicate_of IS NULL;
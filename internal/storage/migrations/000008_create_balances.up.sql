@@ -0,0 +1,23 @@
+CREATE TABLE balances (
+    user_id BIGINT NOT NULL PRIMARY KEY,
+    current DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    withdrawn DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    version BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_balances_user_id
+    FOREIGN KEY (user_id)
+    REFERENCES users (id)
+    ON UPDATE CASCADE
+    ON DELETE RESTRICT
+);
+
+INSERT INTO balances (user_id, current, withdrawn, version, updated_at)
+SELECT 
+    user_id, 
+    SUM(amount), 
+    SUM(CASE WHEN type IN ('withdrawal', 'refund') THEN -amount ELSE 0 END), 
+    1, 
+    NOW()
+FROM balance_logs
+GROUP BY user_id;
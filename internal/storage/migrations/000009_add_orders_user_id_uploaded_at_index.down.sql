@@ -0,0 +1 @@
+DROP INDEX idx_orders_user_id_uploaded_at;
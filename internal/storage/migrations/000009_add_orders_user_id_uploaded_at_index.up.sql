@@ -0,0 +1 @@
+CREATE INDEX idx_orders_user_id_uploaded_at ON orders (user_id, uploaded_at DESC, number DESC);
@@ -0,0 +1 @@
+DROP INDEX idx_balance_logs_withdrawals;
@@ -0,0 +1 @@
+CREATE INDEX idx_balance_logs_withdrawals ON balance_logs (user_id, processed_at DESC, id DESC) WHERE type = 'withdrawal';
@@ -0,0 +1 @@
+CREATE INDEX idx_balance_logs_user_id_processed_at ON balance_logs (user_id, processed_at, id);
@@ -0,0 +1,20 @@
+CREATE TABLE sessions (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id BIGINT NOT NULL,
+    refresh_token_hash BYTEA NOT NULL,
+    previous_refresh_token_hash BYTEA NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+
+    CONSTRAINT fk_sessions_user_id
+    FOREIGN KEY (user_id)
+    REFERENCES users (id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_sessions_refresh_token_hash ON sessions (refresh_token_hash);
+
+CREATE INDEX idx_sessions_previous_refresh_token_hash ON sessions (previous_refresh_token_hash) 
+WHERE previous_refresh_token_hash IS NOT NULL;
@@ -0,0 +1,9 @@
+CREATE TABLE login_attempts (
+    kind VARCHAR(10) NOT NULL,
+    value VARCHAR(100) NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    blocked_until TIMESTAMP NULL,
+
+    PRIMARY KEY (kind, value)
+);
@@ -0,0 +1,22 @@
+CREATE TABLE order_status_history (
+    id BIGINT NOT NULL GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    order_number VARCHAR(30) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_order_status_history_order_number
+    FOREIGN KEY (order_number)
+    REFERENCES orders (number)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_order_status_history_order_number ON order_status_history (order_number, changed_at, id);
+
+-- время прошлых переходов неизвестно, сохраняется только загрузка и текущий статус
+INSERT INTO order_status_history (order_number, status, source, changed_at)
+SELECT number, 'NEW', 'user', uploaded_at FROM orders;
+
+INSERT INTO order_status_history (order_number, status, source, changed_at)
+SELECT number, status, 'migration', NOW() FROM orders WHERE status <> 'NEW';
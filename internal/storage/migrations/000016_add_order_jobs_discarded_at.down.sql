@@ -0,0 +1,4 @@
+DELETE FROM order_jobs WHERE discarded_at IS NOT NULL;
+
+ALTER TABLE order_jobs
+    DROP COLUMN discarded_at;
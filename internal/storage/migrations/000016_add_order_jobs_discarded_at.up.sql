@@ -0,0 +1,2 @@
+ALTER TABLE order_jobs
+    ADD COLUMN discarded_at TIMESTAMP NULL;
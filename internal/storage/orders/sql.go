@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
 	"github.com/aleksandrpnshkn/gophermart/internal/storage/balance"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -29,10 +31,10 @@ func (s *SQLStorage) GetByNumber(
 	var order models.Order
 
 	row := s.pgxpool.QueryRow(ctx, `
-        SELECT number, user_id, status FROM orders 
+        SELECT number, user_id, status, accrual, uploaded_at FROM orders 
         WHERE number = $1
     `, orderNumber)
-	err := row.Scan(&order.OrderNumber, &order.UserID, &order.Status)
+	err := row.Scan(&order.OrderNumber, &order.UserID, &order.Status, &order.Accrual, &order.UploadedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.Order{}, ErrOrderNotFound
@@ -43,18 +45,124 @@ func (s *SQLStorage) GetByNumber(
 	return order, nil
 }
 
+const userOrdersFilterCondition = `
+    user_id = @user_id
+    AND (cardinality(@statuses::VARCHAR[]) = 0 OR status = ANY(@statuses::VARCHAR[]))
+    AND (@from::TIMESTAMP IS NULL OR uploaded_at >= @from::TIMESTAMP)
+    AND (@to::TIMESTAMP IS NULL OR uploaded_at < @to::TIMESTAMP)
+`
+
+func userOrdersFilterArgs(user models.User, filter models.OrdersFilter) pgx.NamedArgs {
+	statuses := make([]string, 0, len(filter.Statuses))
+	for _, status := range filter.Statuses {
+		statuses = append(statuses, string(status))
+	}
+
+	return pgx.NamedArgs{
+		"user_id":  user.ID,
+		"statuses": statuses,
+		"from":     nullTime(filter.From),
+		"to":       nullTime(filter.To),
+	}
+}
+
 func (s *SQLStorage) GetUserOrders(
 	ctx context.Context,
 	user models.User,
+	filter models.OrdersFilter,
+) ([]models.Order, error) {
+	orders := []models.Order{}
+
+	args := userOrdersFilterArgs(user, filter)
+	args["after_at"] = nullTime(filter.After.At)
+	args["after_number"] = filter.After.Key
+	args["limit"] = filter.Limit
+
+	rows, err := s.pgxpool.Query(ctx, `
+        SELECT number, user_id, status, accrual, uploaded_at 
+        FROM orders 
+        WHERE `+userOrdersFilterCondition+`
+            AND (@after_at::TIMESTAMP IS NULL OR (uploaded_at, number) < (@after_at::TIMESTAMP, @after_number))
+        ORDER BY uploaded_at DESC, number DESC
+        LIMIT NULLIF(@limit::INT, 0)
+    `, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var order models.Order
+
+		err = rows.Scan(
+			&order.OrderNumber,
+			&order.UserID,
+			&order.Status,
+			&order.Accrual,
+			&order.UploadedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, order)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (s *SQLStorage) CountUserOrders(
+	ctx context.Context,
+	user models.User,
+	filter models.OrdersFilter,
+) (int, error) {
+	var total int
+
+	row := s.pgxpool.QueryRow(ctx, `
+        SELECT COUNT(*) 
+        FROM orders 
+        WHERE `+userOrdersFilterCondition, userOrdersFilterArgs(user, filter))
+	err := row.Scan(&total)
+
+	return total, err
+}
+
+func (s *SQLStorage) GetUnfinishedOrders(
+	ctx context.Context,
+	afterNumber string,
+	limit int,
 ) ([]models.Order, error) {
 	orders := []models.Order{}
 
 	rows, err := s.pgxpool.Query(ctx, `
         SELECT number, user_id, status, accrual, uploaded_at 
         FROM orders 
-        WHERE user_id = $1
-        ORDER BY uploaded_at DESC
-    `, user.ID)
+        WHERE status IN (@status_new, @status_processing) AND number > @after_number
+            AND NOT EXISTS (
+                SELECT 1 FROM order_jobs 
+                WHERE order_jobs.order_number = orders.number AND order_jobs.discarded_at IS NOT NULL
+            )
+            AND (
+                EXISTS (SELECT 1 FROM order_jobs WHERE order_jobs.order_number = orders.number)
+                OR NOT EXISTS (
+                    SELECT 1 FROM balance_logs 
+                    WHERE balance_logs.order_number = orders.number AND balance_logs.type = @type_withdrawal
+                )
+            )
+        ORDER BY number
+        LIMIT @limit
+    `, pgx.NamedArgs{
+		"status_new":        types.OrderStatusNew,
+		"status_processing": types.OrderStatusProcessing,
+		"type_withdrawal":   types.BalanceChangeTypeWithdrawal,
+		"after_number":      afterNumber,
+		"limit":             limit,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +197,13 @@ func (s *SQLStorage) Create(
 	ctx context.Context,
 	order models.Order,
 ) (models.Order, error) {
-	_, err := s.pgxpool.Exec(ctx, `
+	tx, err := s.pgxpool.Begin(ctx)
+	if err != nil {
+		return models.Order{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
         INSERT INTO orders (number, user_id, status, accrual, uploaded_at) 
         VALUES (@number, @user_id, @status, @accrual, @uploaded_at)
     `, pgx.NamedArgs{
@@ -101,8 +215,13 @@ func (s *SQLStorage) Create(
 	})
 
 	if err == nil {
-		return order, nil
+		err = addStatusChange(ctx, tx, order.OrderNumber, order.Status, types.OrderStatusSourceUser)
+		if err != nil {
+			return models.Order{}, err
+		}
+		return order, tx.Commit(ctx)
 	}
+	tx.Rollback(ctx)
 
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) && pgErr.Code != pgerrcode.UniqueViolation {
@@ -124,8 +243,23 @@ func (s *SQLStorage) Create(
 func (s *SQLStorage) UpdateStatus(
 	ctx context.Context,
 	order models.Order,
+	source types.OrderStatusSource,
 ) error {
-	_, err := s.pgxpool.Exec(ctx, `
+	tx, err := s.pgxpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	currentStatus, err := lockUnfinished(ctx, tx, order.OrderNumber)
+	if err != nil {
+		return err
+	}
+	if currentStatus == order.Status {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
         UPDATE orders 
         SET status = @status
         WHERE number = @number
@@ -133,12 +267,22 @@ func (s *SQLStorage) UpdateStatus(
 		"number": order.OrderNumber,
 		"status": order.Status,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	err = addStatusChange(ctx, tx, order.OrderNumber, order.Status, source)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (s *SQLStorage) UpdateAccrual(
 	ctx context.Context,
 	order models.Order,
+	source types.OrderStatusSource,
 ) error {
 	tx, err := s.pgxpool.Begin(ctx)
 	if err != nil {
@@ -146,6 +290,11 @@ func (s *SQLStorage) UpdateAccrual(
 	}
 	defer tx.Rollback(ctx)
 
+	_, err = lockUnfinished(ctx, tx, order.OrderNumber)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(ctx, `
         UPDATE orders 
         SET status = @status, accrual = @accrual
@@ -159,10 +308,16 @@ func (s *SQLStorage) UpdateAccrual(
 		return err
 	}
 
-	_, err = tx.Exec(ctx, balance.ChangeBalanceQuery, pgx.NamedArgs{
-		"order_number": order.OrderNumber,
-		"user_id":      order.UserID,
-		"amount":       order.Accrual,
+	err = addStatusChange(ctx, tx, order.OrderNumber, order.Status, source)
+	if err != nil {
+		return err
+	}
+
+	err = balance.ApplyChange(ctx, tx, models.BalanceChange{
+		OrderNumber: order.OrderNumber,
+		UserID:      order.UserID,
+		Amount:      order.Accrual,
+		Type:        types.BalanceChangeTypeAccrual,
 	})
 	if err != nil {
 		return err
@@ -171,6 +326,83 @@ func (s *SQLStorage) UpdateAccrual(
 	return tx.Commit(ctx)
 }
 
+func (s *SQLStorage) GetStatusHistory(
+	ctx context.Context,
+	orderNumber string,
+) ([]models.OrderStatusChange, error) {
+	rows, err := s.pgxpool.Query(ctx, `
+        SELECT status, source, changed_at FROM order_status_history
+        WHERE order_number = $1
+        ORDER BY changed_at, id
+    `, orderNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.OrderStatusChange{}
+	for rows.Next() {
+		var change models.OrderStatusChange
+		err := rows.Scan(&change.Status, &change.Source, &change.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, change)
+	}
+
+	return history, rows.Err()
+}
+
+// lockUnfinished блокирует заказ до конца транзакции. Конкурентное обновление дождётся блокировки
+// и увидит итоговый статус, поэтому обработанный заказ не возвращается в работу, а баллы начисляются один раз.
+func lockUnfinished(ctx context.Context, tx pgx.Tx, orderNumber string) (types.OrderStatus, error) {
+	var status types.OrderStatus
+
+	err := tx.QueryRow(ctx, `
+        SELECT status FROM orders
+        WHERE number = $1
+        FOR UPDATE
+    `, orderNumber).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return status, ErrOrderNotFound
+		}
+		return status, err
+	}
+
+	if status == types.OrderStatusProcessed || status == types.OrderStatusInvalid {
+		return status, ErrOrderAlreadyFinished
+	}
+
+	return status, nil
+}
+
+func addStatusChange(
+	ctx context.Context,
+	tx pgx.Tx,
+	orderNumber string,
+	status types.OrderStatus,
+	source types.OrderStatusSource,
+) error {
+	_, err := tx.Exec(ctx, `
+        INSERT INTO order_status_history (order_number, status, source)
+        VALUES (@order_number, @status, @source)
+    `, pgx.NamedArgs{
+		"order_number": orderNumber,
+		"status":       status,
+		"source":       source,
+	})
+	return err
+}
+
+// nullTime передаёт нулевое время как NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 func (s *SQLStorage) Close() error {
 	s.pgxpool.Close()
 	return nil
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/aleksandrpnshkn/gophermart/internal/types"
 )
 
 type Storage interface {
@@ -12,13 +13,26 @@ type Storage interface {
 
 	GetByNumber(ctx context.Context, orderNumber string) (models.Order, error)
 
-	GetUserOrders(ctx context.Context, user models.User) ([]models.Order, error)
+	// GetUserOrders возвращает заказы пользователя от новых к старым
+	GetUserOrders(ctx context.Context, user models.User, filter models.OrdersFilter) ([]models.Order, error)
+
+	// CountUserOrders считает заказы пользователя по фильтру без учёта курсора и лимита
+	CountUserOrders(ctx context.Context, user models.User, filter models.OrdersFilter) (int, error)
+
+	// GetUnfinishedOrders возвращает заказы в статусах NEW и PROCESSING с номером больше afterNumber
+	// Заказы, созданные только под списание баллов, и заказы с выброшенной из dead letter задачей сюда не попадают
+	GetUnfinishedOrders(ctx context.Context, afterNumber string, limit int) ([]models.Order, error)
 
 	Create(ctx context.Context, order models.Order) (models.Order, error)
 
-	UpdateStatus(ctx context.Context, order models.Order) error
+	// UpdateStatus и UpdateAccrual записывают переход в историю статусов и не меняют
+	// заказы в статусах PROCESSED и INVALID, для них возвращается ErrOrderAlreadyFinished
+	UpdateStatus(ctx context.Context, order models.Order, source types.OrderStatusSource) error
+
+	UpdateAccrual(ctx context.Context, order models.Order, source types.OrderStatusSource) error
 
-	UpdateAccrual(ctx context.Context, order models.Order) error
+	// GetStatusHistory возвращает переходы заказа от первого к последнему
+	GetStatusHistory(ctx context.Context, orderNumber string) ([]models.OrderStatusChange, error)
 
 	Close() error
 }
@@ -27,4 +41,5 @@ var (
 	ErrOrderNotFound                    = errors.New("order not found")
 	ErrOrderAlreadyCreated              = errors.New("order already created")
 	ErrOrderAlreadyCreatedByAnotherUser = errors.New("order already created by another user")
+	ErrOrderAlreadyFinished             = errors.New("order already finished")
 )
@@ -0,0 +1,184 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+type SQLStorage struct {
+	pgxpool *pgxpool.Pool
+}
+
+func (s *SQLStorage) Ping(ctx context.Context) error {
+	return s.pgxpool.Ping(ctx)
+}
+
+func (s *SQLStorage) Create(
+	ctx context.Context,
+	userID int64,
+	refreshTokenHash []byte,
+	ttl time.Duration,
+) (models.Session, error) {
+	var session models.Session
+
+	row := s.pgxpool.QueryRow(ctx, `
+        INSERT INTO sessions (user_id, refresh_token_hash, expires_at) 
+        VALUES (@user_id, @refresh_token_hash, NOW() + make_interval(secs => @ttl_seconds)) 
+        RETURNING id, user_id, created_at, expires_at
+    `, pgx.NamedArgs{
+		"user_id":            userID,
+		"refresh_token_hash": refreshTokenHash,
+		"ttl_seconds":        ttl.Seconds(),
+	})
+	err := row.Scan(&session.ID, &session.UserID, &session.CreatedAt, &session.ExpiresAt)
+	if err != nil {
+		return models.Session{}, err
+	}
+
+	return session, nil
+}
+
+func (s *SQLStorage) GetByID(ctx context.Context, id string) (models.Session, error) {
+	var session models.Session
+	var revokedAt sql.NullTime
+
+	row := s.pgxpool.QueryRow(ctx, `
+        SELECT id, user_id, created_at, expires_at, revoked_at FROM sessions 
+        WHERE id = @id
+    `, pgx.NamedArgs{
+		"id": id,
+	})
+	err := row.Scan(&session.ID, &session.UserID, &session.CreatedAt, &session.ExpiresAt, &revokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Session{}, ErrSessionNotFound
+		}
+		return models.Session{}, err
+	}
+	if revokedAt.Valid {
+		session.RevokedAt = revokedAt.Time
+	}
+
+	return session, nil
+}
+
+func (s *SQLStorage) Rotate(
+	ctx context.Context,
+	oldHash []byte,
+	newHash []byte,
+	ttl time.Duration,
+) (models.Session, error) {
+	var session models.Session
+
+	row := s.pgxpool.QueryRow(ctx, `
+        UPDATE sessions 
+        SET previous_refresh_token_hash = refresh_token_hash, 
+            refresh_token_hash = @new_hash, 
+            expires_at = NOW() + make_interval(secs => @ttl_seconds)
+        WHERE refresh_token_hash = @old_hash AND revoked_at IS NULL AND expires_at > NOW()
+        RETURNING id, user_id, created_at, expires_at
+    `, pgx.NamedArgs{
+		"old_hash":    oldHash,
+		"new_hash":    newHash,
+		"ttl_seconds": ttl.Seconds(),
+	})
+	err := row.Scan(&session.ID, &session.UserID, &session.CreatedAt, &session.ExpiresAt)
+	if err == nil {
+		return session, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.Session{}, err
+	}
+
+	// Старый токен мог утечь: отзываем сессию, чтобы им не смогли воспользоваться
+	tag, err := s.pgxpool.Exec(ctx, `
+        UPDATE sessions SET revoked_at = NOW() 
+        WHERE previous_refresh_token_hash = @old_hash AND revoked_at IS NULL
+    `, pgx.NamedArgs{
+		"old_hash": oldHash,
+	})
+	if err != nil {
+		return models.Session{}, err
+	}
+	if tag.RowsAffected() > 0 {
+		return models.Session{}, ErrRefreshTokenReused
+	}
+
+	return models.Session{}, ErrSessionNotFound
+}
+
+func (s *SQLStorage) Revoke(ctx context.Context, id string) error {
+	tag, err := s.pgxpool.Exec(ctx, `
+        UPDATE sessions SET revoked_at = COALESCE(revoked_at, NOW()) 
+        WHERE id = @id
+    `, pgx.NamedArgs{
+		"id": id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLStorage) RevokeByRefreshToken(ctx context.Context, refreshTokenHash []byte) error {
+	tag, err := s.pgxpool.Exec(ctx, `
+        UPDATE sessions SET revoked_at = COALESCE(revoked_at, NOW()) 
+        WHERE refresh_token_hash = @hash OR previous_refresh_token_hash = @hash
+    `, pgx.NamedArgs{
+		"hash": refreshTokenHash,
+	})
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLStorage) RevokeAllByUser(ctx context.Context, userID int64) error {
+	_, err := s.pgxpool.Exec(ctx, `
+        UPDATE sessions SET revoked_at = NOW() 
+        WHERE user_id = @user_id AND revoked_at IS NULL
+    `, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	return err
+}
+
+func (s *SQLStorage) Close() error {
+	s.pgxpool.Close()
+	return nil
+}
+
+func NewSQLStorage(ctx context.Context, databaseDSN string) (*SQLStorage, error) {
+	pool, err := pgxpool.New(ctx, databaseDSN)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := SQLStorage{
+		pgxpool: pool,
+	}
+
+	err = storage.Ping(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage, nil
+}
@@ -0,0 +1,34 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aleksandrpnshkn/gophermart/internal/models"
+)
+
+type Storage interface {
+	Ping(ctx context.Context) error
+
+	Create(ctx context.Context, userID int64, refreshTokenHash []byte, ttl time.Duration) (models.Session, error)
+
+	GetByID(ctx context.Context, id string) (models.Session, error)
+
+	// Rotate заменяет refresh токен активной сессии на новый и продлевает её на ttl.
+	// Повторное использование уже заменённого токена отзывает сессию целиком.
+	Rotate(ctx context.Context, oldHash []byte, newHash []byte, ttl time.Duration) (models.Session, error)
+
+	Revoke(ctx context.Context, id string) error
+
+	RevokeByRefreshToken(ctx context.Context, refreshTokenHash []byte) error
+
+	RevokeAllByUser(ctx context.Context, userID int64) error
+
+	Close() error
+}
+
+var (
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
@@ -5,17 +5,26 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/apikeys"
 	"github.com/aleksandrpnshkn/gophermart/internal/storage/balance"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/jobs"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/loginattempts"
 	"github.com/aleksandrpnshkn/gophermart/internal/storage/orders"
+	"github.com/aleksandrpnshkn/gophermart/internal/storage/sessions"
 	"github.com/aleksandrpnshkn/gophermart/internal/storage/users"
 	"github.com/golang-migrate/migrate/v4"
 	"go.uber.org/zap"
 )
 
 type Storages struct {
-	Orders  orders.Storage
-	Users   users.Storage
-	Balance balance.Storage
+	Orders   orders.Storage
+	Users    users.Storage
+	Balance  balance.Storage
+	Jobs     jobs.Storage
+	Sessions sessions.Storage
+
+	LoginAttempts loginattempts.Storage
+	APIKeys       apikeys.Storage
 }
 
 func (s *Storages) Close() error {
@@ -34,6 +43,26 @@ func (s *Storages) Close() error {
 		return err
 	}
 
+	err = s.Jobs.Close()
+	if err != nil {
+		return err
+	}
+
+	err = s.Sessions.Close()
+	if err != nil {
+		return err
+	}
+
+	err = s.LoginAttempts.Close()
+	if err != nil {
+		return err
+	}
+
+	err = s.APIKeys.Close()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -66,9 +95,34 @@ func NewStorages(
 		return nil, fmt.Errorf("failed to init balance SQL storage: %w", err)
 	}
 
+	jobsStorage, err := jobs.NewSQLStorage(ctx, databaseDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init jobs SQL storage: %w", err)
+	}
+
+	sessionsStorage, err := sessions.NewSQLStorage(ctx, databaseDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init sessions SQL storage: %w", err)
+	}
+
+	loginAttemptsStorage, err := loginattempts.NewSQLStorage(ctx, databaseDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init login attempts SQL storage: %w", err)
+	}
+
+	apiKeysStorage, err := apikeys.NewSQLStorage(ctx, databaseDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init api keys SQL storage: %w", err)
+	}
+
 	return &Storages{
-		Orders:  ordersStorage,
-		Users:   usersStorage,
-		Balance: balanceStorage,
+		Orders:   ordersStorage,
+		Users:    usersStorage,
+		Balance:  balanceStorage,
+		Jobs:     jobsStorage,
+		Sessions: sessionsStorage,
+
+		LoginAttempts: loginAttemptsStorage,
+		APIKeys:       apiKeysStorage,
 	}, nil
 }
@@ -26,10 +26,10 @@ func (s *SQLStorage) GetByID(ctx context.Context, id int64) (models.User, error)
 	var user models.User
 
 	row := s.pgxpool.QueryRow(ctx, `
-        SELECT id, login, password_hash FROM users 
+        SELECT id, login, password_hash, role, locked_at IS NOT NULL FROM users 
         WHERE id = $1
     `, id)
-	err := row.Scan(&user.ID, &user.Login, &user.Hash)
+	err := row.Scan(&user.ID, &user.Login, &user.Hash, &user.Role, &user.IsLocked)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.User{}, ErrUserNotFound
@@ -44,10 +44,10 @@ func (s *SQLStorage) GetByLogin(ctx context.Context, login string) (models.User,
 	var user models.User
 
 	row := s.pgxpool.QueryRow(ctx, `
-        SELECT id, login, password_hash FROM users 
+        SELECT id, login, password_hash, role, locked_at IS NOT NULL FROM users 
         WHERE login = $1
     `, login)
-	err := row.Scan(&user.ID, &user.Login, &user.Hash)
+	err := row.Scan(&user.ID, &user.Login, &user.Hash, &user.Role, &user.IsLocked)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.User{}, ErrUserNotFound
@@ -68,12 +68,12 @@ func (s *SQLStorage) Create(
 	row := s.pgxpool.QueryRow(ctx, `
         INSERT INTO users (id, login, password_hash) 
         VALUES (DEFAULT, @login, @password_hash) 
-        RETURNING id, login, password_hash
+        RETURNING id, login, password_hash, role, locked_at IS NOT NULL
     `, pgx.NamedArgs{
 		"login":         login,
 		"password_hash": password,
 	})
-	err := row.Scan(&user.ID, &user.Login, &user.Hash)
+	err := row.Scan(&user.ID, &user.Login, &user.Hash, &user.Role, &user.IsLocked)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
@@ -86,6 +86,81 @@ func (s *SQLStorage) Create(
 	return user, nil
 }
 
+func (s *SQLStorage) Search(ctx context.Context, login string, limit int) ([]models.User, error) {
+	users := []models.User{}
+
+	rows, err := s.pgxpool.Query(ctx, `
+        SELECT id, login, password_hash, role, locked_at IS NOT NULL FROM users 
+        WHERE position(lower(@login) in lower(login)) > 0
+        ORDER BY login
+        LIMIT @limit
+    `, pgx.NamedArgs{
+		"login": login,
+		"limit": limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user models.User
+
+		err = rows.Scan(&user.ID, &user.Login, &user.Hash, &user.Role, &user.IsLocked)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (s *SQLStorage) SetLocked(ctx context.Context, id int64, locked bool) error {
+	tag, err := s.pgxpool.Exec(ctx, `
+        UPDATE users 
+        SET locked_at = CASE WHEN @locked THEN COALESCE(locked_at, NOW()) ELSE NULL END
+        WHERE id = @id
+    `, pgx.NamedArgs{
+		"id":     id,
+		"locked": locked,
+	})
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLStorage) UpdatePasswordHash(ctx context.Context, id int64, hash types.PasswordHash) error {
+	tag, err := s.pgxpool.Exec(ctx, `
+        UPDATE users SET password_hash = @password_hash 
+        WHERE id = @id
+    `, pgx.NamedArgs{
+		"id":            id,
+		"password_hash": hash,
+	})
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (s *SQLStorage) Close() error {
 	s.pgxpool.Close()
 	return nil
@@ -17,6 +17,13 @@ type Storage interface {
 
 	Create(ctx context.Context, login string, hash types.PasswordHash) (models.User, error)
 
+	// Search ищет пользователей по подстроке логина без учёта регистра
+	Search(ctx context.Context, login string, limit int) ([]models.User, error)
+
+	SetLocked(ctx context.Context, id int64, locked bool) error
+
+	UpdatePasswordHash(ctx context.Context, id int64, hash types.PasswordHash) error
+
 	Close() error
 }
 
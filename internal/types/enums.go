@@ -15,3 +15,65 @@ const (
 	// данные по заказу проверены и информация о расчёте успешно получена
 	OrderStatusProcessed OrderStatus = "PROCESSED"
 )
+
+type UserRole string
+
+const (
+	// покупатель, работает только со своими заказами и баллами
+	UserRoleCustomer UserRole = "customer"
+
+	// сотрудник поддержки, имеет доступ к админскому API
+	UserRoleAdmin UserRole = "admin"
+)
+
+type BalanceChangeType string
+
+const (
+	// начисление баллов за заказ
+	BalanceChangeTypeAccrual BalanceChangeType = "accrual"
+
+	// списание баллов в счёт оплаты заказа
+	BalanceChangeTypeWithdrawal BalanceChangeType = "withdrawal"
+
+	// ручная корректировка баланса оператором
+	BalanceChangeTypeAdjustment BalanceChangeType = "adjustment"
+
+	// возврат списанных баллов после отмены заказа
+	BalanceChangeTypeRefund BalanceChangeType = "refund"
+)
+
+type WithdrawalStatus string
+
+const (
+	// баллы списаны в счёт оплаты заказа
+	WithdrawalStatusProcessed WithdrawalStatus = "PROCESSED"
+
+	// заказ отменён, списанные баллы возвращены
+	WithdrawalStatusRefunded WithdrawalStatus = "REFUNDED"
+)
+
+type APIKeyScope string
+
+const (
+	// загрузка номеров заказов от имени пользователя
+	APIKeyScopeOrdersWrite APIKeyScope = "orders:write"
+
+	// просмотр баланса пользователя
+	APIKeyScopeBalanceRead APIKeyScope = "balance:read"
+)
+
+type OrderStatusSource string
+
+const (
+	// заказ загружен пользователем или партнёром по API ключу
+	OrderStatusSourceUser OrderStatusSource = "user"
+
+	// статус получен опросом accrual
+	OrderStatusSourcePoller OrderStatusSource = "poller"
+
+	// статус прислан accrual через вебхук
+	OrderStatusSourceWebhook OrderStatusSource = "webhook"
+
+	// статус перенесён из заказов, созданных до появления истории
+	OrderStatusSourceMigration OrderStatusSource = "migration"
+)